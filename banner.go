@@ -0,0 +1,386 @@
+package unidoc
+
+import (
+	"cmp"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// A bitmapFont holds the glyphs of a font as rows of pixels, where # marks
+// ink. Glyphs may differ in width but not in height.
+type bitmapFont map[rune][]string
+
+// largeFont is a 5×7 font, with narrower glyphs for punctuation.
+var largeFont = bitmapFont{
+	'A':  {" ### ", "#   #", "#   #", "#####", "#   #", "#   #", "#   #"},
+	'B':  {"#### ", "#   #", "#   #", "#### ", "#   #", "#   #", "#### "},
+	'C':  {" ### ", "#   #", "#    ", "#    ", "#    ", "#   #", " ### "},
+	'D':  {"#### ", "#   #", "#   #", "#   #", "#   #", "#   #", "#### "},
+	'E':  {"#####", "#    ", "#    ", "#### ", "#    ", "#    ", "#####"},
+	'F':  {"#####", "#    ", "#    ", "#### ", "#    ", "#    ", "#    "},
+	'G':  {" ### ", "#   #", "#    ", "# ###", "#   #", "#   #", " ####"},
+	'H':  {"#   #", "#   #", "#   #", "#####", "#   #", "#   #", "#   #"},
+	'I':  {"###", " # ", " # ", " # ", " # ", " # ", "###"},
+	'J':  {"  ###", "   # ", "   # ", "   # ", "   # ", "#  # ", " ##  "},
+	'K':  {"#   #", "#  # ", "# #  ", "##   ", "# #  ", "#  # ", "#   #"},
+	'L':  {"#    ", "#    ", "#    ", "#    ", "#    ", "#    ", "#####"},
+	'M':  {"#   #", "## ##", "# # #", "# # #", "#   #", "#   #", "#   #"},
+	'N':  {"#   #", "#   #", "##  #", "# # #", "#  ##", "#   #", "#   #"},
+	'O':  {" ### ", "#   #", "#   #", "#   #", "#   #", "#   #", " ### "},
+	'P':  {"#### ", "#   #", "#   #", "#### ", "#    ", "#    ", "#    "},
+	'Q':  {" ### ", "#   #", "#   #", "#   #", "# # #", "#  # ", " ## #"},
+	'R':  {"#### ", "#   #", "#   #", "#### ", "# #  ", "#  # ", "#   #"},
+	'S':  {" ####", "#    ", "#    ", " ### ", "    #", "    #", "#### "},
+	'T':  {"#####", "  #  ", "  #  ", "  #  ", "  #  ", "  #  ", "  #  "},
+	'U':  {"#   #", "#   #", "#   #", "#   #", "#   #", "#   #", " ### "},
+	'V':  {"#   #", "#   #", "#   #", "#   #", "#   #", " # # ", "  #  "},
+	'W':  {"#   #", "#   #", "#   #", "# # #", "# # #", "# # #", " # # "},
+	'X':  {"#   #", "#   #", " # # ", "  #  ", " # # ", "#   #", "#   #"},
+	'Y':  {"#   #", "#   #", " # # ", "  #  ", "  #  ", "  #  ", "  #  "},
+	'Z':  {"#####", "    #", "   # ", "  #  ", " #   ", "#    ", "#####"},
+	'0':  {" ### ", "#   #", "#  ##", "# # #", "##  #", "#   #", " ### "},
+	'1':  {" # ", "## ", " # ", " # ", " # ", " # ", "###"},
+	'2':  {" ### ", "#   #", "    #", "   # ", "  #  ", " #   ", "#####"},
+	'3':  {"#####", "   # ", "  #  ", "   # ", "    #", "#   #", " ### "},
+	'4':  {"   # ", "  ## ", " # # ", "#  # ", "#####", "   # ", "   # "},
+	'5':  {"#####", "#    ", "#### ", "    #", "    #", "#   #", " ### "},
+	'6':  {"  ## ", " #   ", "#    ", "#### ", "#   #", "#   #", " ### "},
+	'7':  {"#####", "    #", "   # ", "  #  ", " #   ", " #   ", " #   "},
+	'8':  {" ### ", "#   #", "#   #", " ### ", "#   #", "#   #", " ### "},
+	'9':  {" ### ", "#   #", "#   #", " ####", "    #", "   # ", " ##  "},
+	' ':  {"   ", "   ", "   ", "   ", "   ", "   ", "   "},
+	'!':  {"#", "#", "#", "#", "#", " ", "#"},
+	'?':  {" ### ", "#   #", "    #", "   # ", "  #  ", "     ", "  #  "},
+	'.':  {" ", " ", " ", " ", " ", " ", "#"},
+	',':  {"  ", "  ", "  ", "  ", "  ", " #", "# "},
+	':':  {" ", " ", "#", " ", " ", " ", "#"},
+	';':  {"  ", "  ", " #", "  ", "  ", " #", "# "},
+	'\'': {"#", "#", " ", " ", " ", " ", " "},
+	'"':  {"# #", "# #", "   ", "   ", "   ", "   ", "   "},
+	'-':  {"    ", "    ", "    ", "####", "    ", "    ", "    "},
+	'+':  {"     ", "  #  ", "  #  ", "#####", "  #  ", "  #  ", "     "},
+	'=':  {"     ", "     ", "#####", "     ", "#####", "     ", "     "},
+	'_':  {"     ", "     ", "     ", "     ", "     ", "     ", "#####"},
+	'*':  {"     ", "# # #", " ### ", "#####", " ### ", "# # #", "     "},
+	'/':  {"    #", "    #", "   # ", "  #  ", " #   ", "#    ", "#    "},
+	'(':  {"  #", " # ", "#  ", "#  ", "#  ", " # ", "  #"},
+	')':  {"#  ", " # ", "  #", "  #", "  #", " # ", "#  "},
+	'<':  {"   #", "  # ", " #  ", "#   ", " #  ", "  # ", "   #"},
+	'>':  {"#   ", " #  ", "  # ", "   #", "  # ", " #  ", "#   "},
+	'&':  {" ##  ", "#  # ", "# #  ", " #   ", "# # #", "#  # ", " ## #"},
+	'#':  {" # # ", " # # ", "#####", " # # ", "#####", " # # ", " # # "},
+	'%':  {"##   ", "##  #", "   # ", "  #  ", " #   ", "#  ##", "   ##"},
+	'@':  {" ### ", "#   #", "# ###", "# # #", "# ###", "#    ", " ####"},
+}
+
+// smallFont is a 3×5 font, with wider glyphs for M, N and W.
+var smallFont = bitmapFont{
+	'A':  {" # ", "# #", "###", "# #", "# #"},
+	'B':  {"## ", "# #", "## ", "# #", "## "},
+	'C':  {" ##", "#  ", "#  ", "#  ", " ##"},
+	'D':  {"## ", "# #", "# #", "# #", "## "},
+	'E':  {"###", "#  ", "## ", "#  ", "###"},
+	'F':  {"###", "#  ", "## ", "#  ", "#  "},
+	'G':  {" ##", "#  ", "# #", "# #", " ##"},
+	'H':  {"# #", "# #", "###", "# #", "# #"},
+	'I':  {"###", " # ", " # ", " # ", "###"},
+	'J':  {"  #", "  #", "  #", "# #", " # "},
+	'K':  {"# #", "# #", "## ", "# #", "# #"},
+	'L':  {"#  ", "#  ", "#  ", "#  ", "###"},
+	'M':  {"#   #", "## ##", "# # #", "#   #", "#   #"},
+	'N':  {"#  #", "## #", "# ##", "#  #", "#  #"},
+	'O':  {" # ", "# #", "# #", "# #", " # "},
+	'P':  {"## ", "# #", "## ", "#  ", "#  "},
+	'Q':  {" # ", "# #", "# #", "## ", " ##"},
+	'R':  {"## ", "# #", "## ", "# #", "# #"},
+	'S':  {" ##", "#  ", " # ", "  #", "## "},
+	'T':  {"###", " # ", " # ", " # ", " # "},
+	'U':  {"# #", "# #", "# #", "# #", "###"},
+	'V':  {"# #", "# #", "# #", "# #", " # "},
+	'W':  {"#   #", "#   #", "# # #", "## ##", "#   #"},
+	'X':  {"# #", "# #", " # ", "# #", "# #"},
+	'Y':  {"# #", "# #", " # ", " # ", " # "},
+	'Z':  {"###", "  #", " # ", "#  ", "###"},
+	'0':  {"###", "# #", "# #", "# #", "###"},
+	'1':  {" # ", "## ", " # ", " # ", "###"},
+	'2':  {"## ", "  #", " # ", "#  ", "###"},
+	'3':  {"## ", "  #", " # ", "  #", "## "},
+	'4':  {"# #", "# #", "###", "  #", "  #"},
+	'5':  {"###", "#  ", "## ", "  #", "## "},
+	'6':  {" ##", "#  ", "###", "# #", "###"},
+	'7':  {"###", "  #", " # ", " # ", " # "},
+	'8':  {"###", "# #", "###", "# #", "###"},
+	'9':  {"###", "# #", "###", "  #", "## "},
+	' ':  {"  ", "  ", "  ", "  ", "  "},
+	'!':  {"#", "#", "#", " ", "#"},
+	'?':  {"## ", "  #", " # ", "   ", " # "},
+	'.':  {" ", " ", " ", " ", "#"},
+	',':  {"  ", "  ", "  ", " #", "# "},
+	':':  {" ", "#", " ", "#", " "},
+	';':  {"  ", " #", "  ", " #", "# "},
+	'\'': {"#", "#", " ", " ", " "},
+	'"':  {"# #", "# #", "   ", "   ", "   "},
+	'-':  {"   ", "   ", "###", "   ", "   "},
+	'+':  {"   ", " # ", "###", " # ", "   "},
+	'=':  {"   ", "###", "   ", "###", "   "},
+	'_':  {"   ", "   ", "   ", "   ", "###"},
+	'*':  {"# #", " # ", "# #", "   ", "   "},
+	'/':  {"  #", "  #", " # ", "#  ", "#  "},
+	'(':  {" #", "# ", "# ", "# ", " #"},
+	')':  {"# ", " #", " #", " #", "# "},
+	'<':  {"  #", " # ", "#  ", " # ", "  #"},
+	'>':  {"#  ", " # ", "  #", " # ", "#  "},
+	'&':  {" # ", "# #", " # ", "# #", " ##"},
+	'#':  {"# #", "###", "# #", "###", "# #"},
+	'%':  {"# #", "  #", " # ", "#  ", "# #"},
+	'@':  {"###", "# #", "# #", "#  ", " ##"},
+}
+
+// diacriticFolds maps uppercase letters with diacritics and ligatures to the
+// plain letters of the fonts.
+var diacriticFolds = func() map[rune]string {
+	folds := map[rune]string{'Æ': "AE", 'Œ': "OE", 'ß': "SS"}
+	for _, letters := range []string{
+		"AÀÁÂÃÄÅĀĂĄ", "CÇĆĈĊČ", "DĎĐÐ", "EÈÉÊËĒĔĖĘĚ", "GĜĞĠĢ", "HĤĦ", "IÌÍÎÏĨĪĬĮİ", "JĴ", "KĶ",
+		"LĹĻĽĿŁ", "NÑŃŅŇ", "OÒÓÔÕÖØŌŎŐ", "RŔŖŘ", "SŚŜŞŠȘ", "TŢŤŦȚ", "UÙÚÛÜŨŪŬŮŰŲ", "WŴ", "YÝŸŶ", "ZŹŻŽ",
+	} {
+		plain, _ := utf8.DecodeRuneInString(letters)
+		for _, r := range letters[utf8.RuneLen(plain):] {
+			folds[r] = string(plain)
+		}
+	}
+	return folds
+}()
+
+// foldDiacritics replaces the letters with diacritics in text by plain
+// letters, so that banners in most Latin scripts do not need glyphs of
+// their own.
+func foldDiacritics(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		if fold, ok := diacriticFolds[unicode.ToUpper(r)]; ok {
+			b.WriteString(fold)
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// glyph returns the glyph of a letter, matching lowercase letters to
+// uppercase ones and missing letters to a question mark.
+func (f bitmapFont) glyph(r rune) []string {
+	if g, ok := f[unicode.ToUpper(r)]; ok {
+		return g
+	}
+	return f['?']
+}
+
+// pixelWidth returns the width of text in pixels, with a column between
+// letters.
+func (f bitmapFont) pixelWidth(text string) int {
+	width := -1
+	for _, r := range text {
+		width += len(f.glyph(r)[0]) + 1
+	}
+	return max(width, 0)
+}
+
+// bitmap returns the pixels of text by row, with a column between letters.
+func (f bitmapFont) bitmap(text string) [][]bool {
+	height := len(f['?'])
+	rows := make([][]bool, height)
+	for i, r := range []rune(text) {
+		for y, line := range f.glyph(r) {
+			if i > 0 {
+				rows[y] = append(rows[y], false)
+			}
+			for _, pixel := range line {
+				rows[y] = append(rows[y], pixel == '#')
+			}
+		}
+	}
+	return rows
+}
+
+// wrap breaks text into lines at most limit pixels wide, at spaces where
+// possible and between letters in words that are too wide by themselves.
+func (f bitmapFont) wrap(text string, limit int) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(text) {
+		if line != "" && f.pixelWidth(line+" "+word) <= limit {
+			line += " " + word
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+		line = ""
+		for _, r := range word {
+			if line != "" && f.pixelWidth(line+string(r)) > limit {
+				lines = append(lines, line)
+				line = ""
+			}
+			line += string(r)
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// halfBlocks maps the upper and lower pixels of a cell to a half block.
+var halfBlocks = [2][2]string{{" ", "▄"}, {"▀", "█"}}
+
+// drawPixels draws rows of pixels in the cells of a banner font.
+func drawPixels(rows [][]bool, font BannerFont) []string {
+	// at reports whether a pixel is inked, treating pixels beyond the
+	// rows as blank
+	at := func(x, y int) bool {
+		return y >= 0 && y < len(rows) && x >= 0 && x < len(rows[y]) && rows[y][x]
+	}
+	width := 0
+	if len(rows) > 0 {
+		width = len(rows[0])
+	}
+
+	var lines []string
+	switch font {
+	case BannerFontShadow:
+		// Cells without ink show the shadow one pixel down and right
+		shadow := func(x, y int) bool {
+			return at(x-1, y-1) || at(x-1, y)
+		}
+		for y := 0; y < len(rows)+1; y += 2 {
+			var b strings.Builder
+			for x := range width + 1 {
+				top, bottom := at(x, y), at(x, y+1)
+				if !top && !bottom && (shadow(x, y) || shadow(x, y+1)) {
+					b.WriteString("░")
+				} else {
+					b.WriteString(halfBlocks[btoi(top)][btoi(bottom)])
+				}
+			}
+			lines = append(lines, b.String())
+		}
+	case BannerFontBraille:
+		for y := 0; y < len(rows); y += 4 {
+			var b strings.Builder
+			for x := 0; x < width; x += 2 {
+				pattern := rune(0)
+				for dy, dots := range brailleDots {
+					for dx, dot := range dots {
+						if at(x+dx, y+dy) {
+							pattern |= dot
+						}
+					}
+				}
+				if pattern == 0 {
+					b.WriteByte(' ')
+				} else {
+					b.WriteRune(0x2800 + pattern)
+				}
+			}
+			lines = append(lines, b.String())
+		}
+	default:
+		for y := 0; y < len(rows); y += 2 {
+			var b strings.Builder
+			for x := range width {
+				b.WriteString(halfBlocks[btoi(at(x, y))][btoi(at(x, y+1))])
+			}
+			lines = append(lines, b.String())
+		}
+	}
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return lines
+}
+
+// btoi returns 1 for true and 0 for false.
+func btoi(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// drawBanner draws text in large letters of a banner font, wrapped by word to
+// width columns and optionally centered. Wrapped lines are set apart by an
+// empty line.
+func drawBanner(text string, font BannerFont, width int, center bool) []string {
+	bitmap, limit := largeFont, width
+	switch font {
+	case BannerFontSmall:
+		bitmap = smallFont
+	case BannerFontShadow:
+		limit = width - 1 // The shadow takes a column on the right
+	case BannerFontBraille:
+		limit = width * 2
+	}
+
+	var lines []string
+	for i, line := range bitmap.wrap(foldDiacritics(text), limit) {
+		if i > 0 {
+			lines = append(lines, "")
+		}
+		rows := drawPixels(bitmap.bitmap(line), font)
+		if center {
+			widest := 0
+			for _, row := range rows {
+				widest = max(widest, textWidth(row))
+			}
+			indent := strings.Repeat(" ", max((width-widest)/2, 0))
+			for i, row := range rows {
+				if row != "" {
+					rows[i] = indent + row
+				}
+			}
+		}
+		lines = append(lines, rows...)
+	}
+	return lines
+}
+
+// bannerProcessor renders ```banner blocks as large letters in a bitmap font
+// chosen by the font attribute, block by default. Every paragraph becomes a
+// banner, wrapped by word and centered with align=center:
+//
+//	```banner {font=shadow align=center}
+//	Release 2.0
+type bannerProcessor struct{}
+
+// ProcessBlock implements BlockProcessor.ProcessBlock.
+func (p bannerProcessor) ProcessBlock(block FencedBlock, width int) ([]string, error) {
+	font := BannerFontBlock
+	if text, ok := block.Attributes["font"]; ok {
+		if err := font.UnmarshalText([]byte(text)); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrUnprocessed, err)
+		}
+	}
+	align := cmp.Or(block.Attributes["align"], "left")
+	if align != "left" && align != "center" {
+		return nil, fmt.Errorf("%w: invalid align: %s", ErrUnprocessed, align)
+	}
+
+	var lines []string
+	for _, paragraph := range strings.Split(strings.TrimSpace(block.Content), "\n\n") {
+		text := strings.Join(strings.Fields(paragraph), " ")
+		if text == "" {
+			continue
+		}
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, drawBanner(text, font, width, align == "center")...)
+	}
+	if len(lines) == 0 {
+		return nil, ErrUnprocessed
+	}
+	return lines, nil
+}
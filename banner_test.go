@@ -0,0 +1,111 @@
+package unidoc
+
+import "testing"
+
+func TestBitmapFontWrap(t *testing.T) {
+	// Block letters are 5 pixels wide with a pixel between them
+	got := largeFont.wrap("ab cd ef", 17)
+	if len(got) != 3 || got[0] != "ab" || got[1] != "cd" || got[2] != "ef" {
+		t.Errorf("wrap = %q", got)
+	}
+}
+
+func TestBanner(t *testing.T) {
+	tests := []struct {
+		name, content string
+		attrs         map[string]string
+		width         int
+		want          string
+	}{
+		{"block", "Hi", nil, 30, `
+█   █ ▀█▀
+█▄▄▄█  █
+█   █  █
+▀   ▀ ▀▀▀`},
+		{"small", "Hi", map[string]string{"font": "small"}, 30, `
+█ █ ▀█▀
+█▀█  █
+▀ ▀ ▀▀▀`},
+		{"shadow", "Hi", map[string]string{"font": "shadow"}, 30, `
+█░  █░▀█▀░
+█▄▄▄█░ █░
+█░░░█░ █░
+▀░  ▀░▀▀▀░`},
+		{"braille", "Hi", map[string]string{"font": "braille"}, 30, `
+⣇⣀⡇⢹⠁
+⠇ ⠇⠼⠄`},
+		{"centered", "Hi", map[string]string{"align": "center"}, 30, `
+          █   █ ▀█▀
+          █▄▄▄█  █
+          █   █  █
+          ▀   ▀ ▀▀▀`},
+		{"wrapped paragraphs", "Go up\n\nNow", map[string]string{"font": "small"}, 12, `
+▄▀▀ ▄▀▄
+█ █ █ █
+ ▀▀  ▀
+
+█ █ █▀▄
+█ █ █▀
+▀▀▀ ▀
+
+█▄ █ ▄▀▄
+█ ▀█ █ █
+▀  ▀  ▀
+
+█   █
+█▄▀▄█
+▀   ▀`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := processTest(t, FencedBlock{Language: "banner", Content: tt.content, Attributes: tt.attrs}, tt.width)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkOutput(t, got, tt.want)
+		})
+	}
+}
+
+func TestBannerHeading(t *testing.T) {
+	got := convertTest(t, "# Hi\n\nText", func(c *Config) {
+		if err := c.Headings.Set("1:font=small"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	checkOutput(t, got, `
+█ █ ▀█▀
+█▀█  █
+▀ ▀ ▀▀▀
+═══════
+
+Text`)
+}
+
+func TestBannerHeadingOption(t *testing.T) {
+	got := convertTest(t, "---\nunidoc:\n  headings:\n    1: {font: small, underline: \"\"}\n---\n\n# Hi")
+	checkOutput(t, got, `
+█ █ ▀█▀
+█▀█  █
+▀ ▀ ▀▀▀`)
+}
+
+func TestBannerDiacritics(t *testing.T) {
+	tests := []struct {
+		text, plain string
+	}{
+		{"Ñoño Crème", "NONO CREME"},
+		{"Straße", "STRASSE"},
+		{"Œuvre Ærø", "OEUVRE AERO"},
+	}
+	for _, tt := range tests {
+		got, err := processTest(t, FencedBlock{Language: "banner", Content: tt.text, Attributes: map[string]string{"font": "small"}}, 80)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, _ := processTest(t, FencedBlock{Language: "banner", Content: tt.plain, Attributes: map[string]string{"font": "small"}}, 80)
+		if got != want {
+			t.Errorf("%s:\n%s\nwant:\n%s", tt.text, got, want)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package unidoc
+
+import (
+	"errors"
+	"strings"
+
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/util"
+)
+
+// A FencedBlock is a fenced code block handed to a BlockProcessor.
+type FencedBlock struct {
+	Language   string            // First word of the info string, such as "csv"
+	Content    string            // Text between the fences
+	Attributes map[string]string // Attributes from the info string: ```csv {delimiter=";"}
+}
+
+// A BlockProcessor renders the fenced code blocks of a language in place of
+// the boxed code rendering.
+type BlockProcessor interface {
+	// ProcessBlock returns the lines of a block rendered to at most width
+	// columns, or ErrUnprocessed to leave the block to the boxed code
+	// rendering. Blocks that fail with other errors fall back to the boxed
+	// code rendering too, rather than failing the document: malformed or
+	// half-typed data, and content too large for width, stay readable as code
+	// while the document is written. Processors wrap such errors with
+	// ErrUnprocessed.
+	ProcessBlock(block FencedBlock, width int) ([]string, error)
+}
+
+// ErrUnprocessed is returned by a BlockProcessor to leave a block to the
+// boxed code rendering.
+var ErrUnprocessed = errors.New("block left unprocessed")
+
+// BlockProcessorFunc adapts a function to the BlockProcessor interface.
+type BlockProcessorFunc func(block FencedBlock, width int) ([]string, error)
+
+// ProcessBlock implements BlockProcessor.ProcessBlock.
+func (f BlockProcessorFunc) ProcessBlock(block FencedBlock, width int) ([]string, error) {
+	return f(block, width)
+}
+
+// RegisterBlockProcessor sets the processor for the fenced code blocks of a
+// language, matched case-insensitively. A nil processor restores the boxed
+// code rendering.
+func (c *Config) RegisterBlockProcessor(language string, processor BlockProcessor) {
+	processors := cloneMap(c.BlockProcessors)
+	if processor == nil {
+		delete(processors, strings.ToLower(language))
+	} else {
+		processors[strings.ToLower(language)] = processor
+	}
+	c.BlockProcessors = processors
+}
+
+// blockAttributes returns the attributes of a node as text.
+func blockAttributes(node gast.Node) map[string]string {
+	attrs := make(map[string]string, len(node.Attributes()))
+	for _, attr := range node.Attributes() {
+		if value, err := optionText(attr.Value); err == nil {
+			attrs[string(attr.Name)] = value
+		}
+	}
+	return attrs
+}
+
+// renderProcessedBlock renders a fenced code block with a BlockProcessor. It
+// returns ErrUnprocessed for blocks the processor leaves alone or fails on,
+// so that they keep the boxed code rendering.
+func (r *UnicodeRenderer) renderProcessedBlock(
+	w util.BufWriter,
+	block FencedBlock,
+	processor BlockProcessor,
+) (gast.WalkStatus, error) {
+	lines, err := processor.ProcessBlock(block, r.width)
+	if err != nil {
+		return gast.WalkContinue, ErrUnprocessed
+	}
+
+	var b strings.Builder
+	for _, line := range lines {
+		b.WriteString(line + "\n")
+	}
+	b.WriteString("\n")
+	if _, err := w.WriteString(b.String()); err != nil {
+		return gast.WalkStop, err
+	}
+	return gast.WalkSkipChildren, nil
+}
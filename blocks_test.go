@@ -0,0 +1,152 @@
+package unidoc
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestBlockProcessor(t *testing.T) {
+	var got FencedBlock
+	var gotWidth int
+	output := convertTest(t, "```Shout {level=3 .loud}\nhello\nworld\n```", func(c *Config) {
+		c.RegisterBlockProcessor("SHOUT", BlockProcessorFunc(func(block FencedBlock, width int) ([]string, error) {
+			got, gotWidth = block, width
+			return []string{"HELLO", "WORLD"}, nil
+		}))
+	})
+	checkOutput(t, output, `
+HELLO
+WORLD`)
+
+	want := FencedBlock{Language: "shout", Content: "hello\nworld\n", Attributes: map[string]string{"level": "3", "class": "loud"}}
+	if fmt.Sprint(got) != fmt.Sprint(want) || gotWidth != 30 {
+		t.Errorf("processor got %q at width %d, want %q at width 30", got, gotWidth, want)
+	}
+}
+
+func TestBlockProcessorFallback(t *testing.T) {
+	// Blocks that the processor leaves alone or fails on are boxed as code
+	for _, err := range []error{ErrUnprocessed, fmt.Errorf("%w: bad data", ErrUnprocessed), errors.New("boom")} {
+		output := convertTest(t, "```x\nhello\n```", func(c *Config) {
+			c.RegisterBlockProcessor("x", BlockProcessorFunc(func(FencedBlock, int) ([]string, error) {
+				return nil, err
+			}))
+		})
+		checkOutput(t, output, `
+┌────────────────────────────┐
+│ hello                      │
+└────────────────────────────┘`)
+	}
+}
+
+func TestBlockProcessorsUnprocessed(t *testing.T) {
+	// Every default processor leaves the blocks it cannot render to the code box
+	structured := map[string]string{"class": "structured"}
+	tests := []struct {
+		name  string
+		block FencedBlock
+		width int
+	}{
+		{"banner empty", FencedBlock{Language: "banner", Content: "\n\n"}, 30},
+		{"banner font", FencedBlock{Language: "banner", Content: "Hi", Attributes: map[string]string{"font": "comic"}}, 30},
+		{"banner align", FencedBlock{Language: "banner", Content: "Hi", Attributes: map[string]string{"align": "right"}}, 30},
+
+		{"chart empty", FencedBlock{Language: "chart", Content: ""}, 30},
+		{"chart text", FencedBlock{Language: "chart", Content: "just text\n"}, 30},
+		{"chart NaN", FencedBlock{Language: "chart", Content: "a: NaN\n"}, 30},
+		{"chart Inf", FencedBlock{Language: "chart", Content: "a: Inf\n"}, 30},
+		{"chart overflow", FencedBlock{Language: "chart", Content: "a: 1e400\n"}, 30},
+		{"chart two values", FencedBlock{Language: "chart", Content: "a: 1 2\n"}, 30},
+		{"chart open quote", FencedBlock{Language: "chart", Content: "a: 1\n\"unclosed,2\n"}, 30},
+		{"chart type", FencedBlock{Language: "chart", Content: "a: 1\n", Attributes: map[string]string{"type": "pie"}}, 30},
+		{"chart height", FencedBlock{Language: "chart", Content: "a: 1\n", Attributes: map[string]string{"type": "column", "height": "0"}}, 30},
+		{"chart max text", FencedBlock{Language: "chart", Content: "a: 1\n", Attributes: map[string]string{"max": "abc"}}, 30},
+		{"chart max negative", FencedBlock{Language: "chart", Content: "a: 1\n", Attributes: map[string]string{"max": "-1"}}, 30},
+		{"chart max NaN", FencedBlock{Language: "chart", Content: "a: 1\n", Attributes: map[string]string{"max": "NaN"}}, 30},
+		{"chart max overflow", FencedBlock{Language: "chart", Content: "a: 1\n", Attributes: map[string]string{"max": "1e400"}}, 30},
+
+		{"csv open quote", FencedBlock{Language: "csv", Content: "a,\"b\n"}, 40},
+		{"csv empty", FencedBlock{Language: "csv", Content: ""}, 40},
+		{"csv delimiter", FencedBlock{Language: "csv", Content: "a,b", Attributes: map[string]string{"delimiter": "ab"}}, 40},
+		{"csv header", FencedBlock{Language: "csv", Content: "a,b", Attributes: map[string]string{"header": "maybe"}}, 40},
+
+		{"diff strike", FencedBlock{Language: "diff", Content: "-x\n", Attributes: map[string]string{"strike": "maybe"}}, 30},
+		{"diff line numbers", FencedBlock{Language: "diff", Content: "-x\n", Attributes: map[string]string{"line-numbers": "often"}}, 30},
+		{"diff too narrow", FencedBlock{Language: "diff", Content: "-x\n"}, 6},
+		{"diff no width", FencedBlock{Language: "diff", Content: "-x\n"}, 0},
+		{"diff numbers too narrow", FencedBlock{Language: "diff", Content: "@@ -1 +1 @@\n-x\n", Attributes: map[string]string{"line-numbers": "true"}}, 10},
+
+		{"flowchart too wide", FencedBlock{Language: "mermaid", Content: "graph TD\n  A[aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa] --> B"}, 20},
+		{"flowchart direction", FencedBlock{Language: "mermaid", Content: "graph XY\n  A --> B"}, 20},
+		{"flowchart no nodes", FencedBlock{Language: "mermaid", Content: "graph TD"}, 20},
+		{"flowchart open shape", FencedBlock{Language: "mermaid", Content: "graph TD\n  A[open --> B"}, 20},
+		{"mermaid other diagram", FencedBlock{Language: "mermaid", Content: "pie\n  \"a\": 1"}, 20},
+
+		{"gantt task", FencedBlock{Language: "gantt", Content: "no colon here"}, 30},
+		{"gantt no start", FencedBlock{Language: "gantt", Content: "A: 5d"}, 30},
+		{"gantt end before start", FencedBlock{Language: "gantt", Content: "A: 2024-07-01, 2024-06-01"}, 30},
+		{"gantt unknown task", FencedBlock{Language: "gantt", Content: "A: after x, 2d"}, 30},
+		{"gantt start", FencedBlock{Language: "gantt", Content: "A: 2024-13-01, 2d"}, 30},
+		{"gantt mermaid", FencedBlock{Language: "mermaid", Content: "gantt\n  A: soon"}, 30},
+		{"timeline no periods", FencedBlock{Language: "timeline", Content: "title Only"}, 30},
+
+		{"qr too wide", FencedBlock{Language: "qr", Content: "hi"}, 28},
+		{"qr too long", FencedBlock{Language: "qr", Content: strings.Repeat("x", 3000)}, 1000},
+		{"qr level", FencedBlock{Language: "qr", Content: "hi", Attributes: map[string]string{"level": "X"}}, 80},
+		{"qr quiet", FencedBlock{Language: "qr", Content: "hi", Attributes: map[string]string{"quiet": "-1"}}, 80},
+		{"qr invert", FencedBlock{Language: "qr", Content: "hi", Attributes: map[string]string{"invert": "sometimes"}}, 80},
+
+		{"sequence too wide", FencedBlock{Language: "sequence", Content: "A->>B: a message far too long for such a narrow page to hold"}, 16},
+		{"sequence end", FencedBlock{Language: "sequence", Content: "end"}, 16},
+		{"sequence no end", FencedBlock{Language: "sequence", Content: "loop x\nA->>B: y"}, 16},
+		{"sequence section", FencedBlock{Language: "sequence", Content: "else z"}, 16},
+		{"sequence statement", FencedBlock{Language: "sequence", Content: "what is this"}, 16},
+		{"sequence no participants", FencedBlock{Language: "sequence", Content: "autonumber"}, 16},
+
+		{"json without class", FencedBlock{Language: "json", Content: `{"a":1}`}, 40},
+		{"json open", FencedBlock{Language: "json", Content: `{"a":1`, Attributes: structured}, 40},
+		{"json two values", FencedBlock{Language: "json", Content: `{"a":1} {"b":2}`, Attributes: structured}, 40},
+		{"json depth", FencedBlock{Language: "json", Content: `{"a":1}`, Attributes: map[string]string{"class": "structured", "depth": "x"}}, 40},
+		{"json items", FencedBlock{Language: "json", Content: `[1]`, Attributes: map[string]string{"class": "structured", "items": "-1"}}, 40},
+		{"yaml open", FencedBlock{Language: "yaml", Content: "a: [1,\n", Attributes: structured}, 40},
+		{"yaml recursive alias", FencedBlock{Language: "yaml", Content: "a: &x [1, *x]\n", Attributes: structured}, 40},
+		{"yaml nested recursive alias", FencedBlock{Language: "yaml", Content: "a: &x {k: [*x]}\n", Attributes: structured}, 40},
+
+		{"tree empty", FencedBlock{Language: "tree", Content: ""}, 30},
+		{"tree blank", FencedBlock{Language: "tree", Content: "\n\n"}, 30},
+		{"tree annotate", FencedBlock{Language: "tree", Content: "a\n", Attributes: map[string]string{"annotate": "colour"}}, 30},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := processTest(t, tt.block, tt.width); !errors.Is(err, ErrUnprocessed) {
+				t.Errorf("got error %v, want ErrUnprocessed", err)
+			}
+		})
+	}
+}
+
+func TestRegisterBlockProcessor(t *testing.T) {
+	config := DefaultConfig()
+	defaults := config.BlockProcessors
+
+	config.RegisterBlockProcessor("CSV", nil)
+	if _, ok := config.BlockProcessors["csv"]; ok {
+		t.Error("csv processor still registered")
+	}
+	if _, ok := defaults["csv"]; !ok {
+		t.Error("csv processor removed from the map of the copied config")
+	}
+	output := convertTest(t, "```csv\na,b\n```", func(c *Config) { *c = config; c.Width = 30 })
+	checkOutput(t, output, `
+┌────────────────────────────┐
+│ a,b                        │
+└────────────────────────────┘`)
+
+	var empty Config
+	empty.RegisterBlockProcessor("x", BlockProcessorFunc(func(FencedBlock, int) ([]string, error) { return nil, nil }))
+	if len(empty.BlockProcessors) != 1 {
+		t.Errorf("processors = %v, want x", empty.BlockProcessors)
+	}
+}
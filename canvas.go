@@ -0,0 +1,192 @@
+package unidoc
+
+import (
+	"strings"
+)
+
+// Directions of the lines through a canvas cell.
+const (
+	lineUp = 1 << iota
+	lineDown
+	lineLeft
+	lineRight
+)
+
+// lineChars maps the directions of the lines through a cell to the character
+// drawing them. Corners are rounded to set lines apart from boxes.
+var lineChars = map[uint8]rune{
+	lineUp: '│', lineDown: '│', lineUp | lineDown: '│',
+	lineLeft: '─', lineRight: '─', lineLeft | lineRight: '─',
+	lineDown | lineRight: '╭', lineDown | lineLeft: '╮',
+	lineUp | lineRight: '╰', lineUp | lineLeft: '╯',
+	lineUp | lineDown | lineRight: '├', lineUp | lineDown | lineLeft: '┤',
+	lineLeft | lineRight | lineDown: '┬', lineLeft | lineRight | lineUp: '┴',
+	lineUp | lineDown | lineLeft | lineRight: '┼',
+}
+
+// A canvas is a grid of character cells to draw diagrams on. Cells hold
+// either a character or the directions of lines, which join into the
+// matching box-drawing character wherever lines meet or cross.
+type canvas struct {
+	width, height int
+	chars         [][]rune  // Characters, 0 for none and -1 for the second column of a wide one
+	lines         [][]uint8 // Directions of the lines through cells
+}
+
+// newCanvas returns an empty canvas.
+func newCanvas(width, height int) *canvas {
+	c := &canvas{width: width, height: height}
+	c.chars = make([][]rune, height)
+	c.lines = make([][]uint8, height)
+	for y := range height {
+		c.chars[y] = make([]rune, width)
+		c.lines[y] = make([]uint8, width)
+	}
+	return c
+}
+
+// inside reports whether a cell lies on the canvas.
+func (c *canvas) inside(x, y int) bool {
+	return x >= 0 && y >= 0 && x < c.width && y < c.height
+}
+
+// empty reports whether a cell on the canvas holds nothing.
+func (c *canvas) empty(x, y int) bool {
+	return c.inside(x, y) && c.chars[y][x] == 0 && c.lines[y][x] == 0
+}
+
+// set puts a character into a cell.
+func (c *canvas) set(x, y int, r rune) {
+	if c.inside(x, y) {
+		c.chars[y][x] = r
+	}
+}
+
+// at returns the character in a cell, or 0 if there is none.
+func (c *canvas) at(x, y int) rune {
+	if !c.inside(x, y) {
+		return 0
+	}
+	return c.chars[y][x]
+}
+
+// text writes text from a cell to the right and returns the column after it.
+// Wide characters take two cells. With overwrite unset, the text stops at the
+// first cell that is not empty.
+func (c *canvas) text(x, y int, s string, overwrite bool) int {
+	for _, r := range s {
+		w := runeWidth(r)
+		if w == 0 {
+			continue
+		}
+		if !c.inside(x+w-1, y) || !overwrite && (!c.empty(x, y) || w == 2 && !c.empty(x+1, y)) {
+			break
+		}
+		c.chars[y][x] = r
+		c.lines[y][x] = 0
+		if w == 2 {
+			c.chars[y][x+1] = -1
+			c.lines[y][x+1] = 0
+		}
+		x += w
+	}
+	return x
+}
+
+// line draws a horizontal or vertical line between two cells.
+func (c *canvas) line(x1, y1, x2, y2 int) {
+	dx, dy := sign(x2-x1), sign(y2-y1)
+	for x, y := x1, y1; x != x2 || y != y2; x, y = x+dx, y+dy {
+		switch {
+		case dx > 0:
+			c.join(x, y, lineRight, x+1, y, lineLeft)
+		case dx < 0:
+			c.join(x, y, lineLeft, x-1, y, lineRight)
+		case dy > 0:
+			c.join(x, y, lineDown, x, y+1, lineUp)
+		default:
+			c.join(x, y, lineUp, x, y-1, lineDown)
+		}
+	}
+}
+
+// join connects two neighboring cells with a line.
+func (c *canvas) join(x1, y1 int, out uint8, x2, y2 int, in uint8) {
+	if c.inside(x1, y1) && c.inside(x2, y2) {
+		c.lines[y1][x1] |= out
+		c.lines[y2][x2] |= in
+	}
+}
+
+// path draws lines through a sequence of points, turning at every point.
+func (c *canvas) path(points [][2]int) {
+	for i := 1; i < len(points); i++ {
+		c.line(points[i-1][0], points[i-1][1], points[i][0], points[i][1])
+	}
+}
+
+// box draws a box with the given corners and text centered on its middle row.
+// The box covers the lines below it.
+func (c *canvas) box(x, y, width, height int, corners string, text string) {
+	r := []rune(corners)
+	for j := y + 1; j < y+height-1; j++ {
+		for i := x + 1; i < x+width-1; i++ {
+			c.set(i, j, ' ')
+		}
+	}
+	for i := x + 1; i < x+width-1; i++ {
+		c.set(i, y, '─')
+		c.set(i, y+height-1, '─')
+	}
+	for j := y + 1; j < y+height-1; j++ {
+		c.set(x, j, '│')
+		c.set(x+width-1, j, '│')
+	}
+	c.set(x, y, r[0])
+	c.set(x+width-1, y, r[1])
+	c.set(x, y+height-1, r[2])
+	c.set(x+width-1, y+height-1, r[3])
+	c.text(x+(width-textWidth(text))/2, y+(height-1)/2, text, true)
+}
+
+// render returns the lines of the canvas without trailing spaces.
+func (c *canvas) render() []string {
+	lines := make([]string, c.height)
+	for y := range c.height {
+		var b strings.Builder
+		for x := range c.width {
+			switch r := c.chars[y][x]; {
+			case r > 0:
+				b.WriteRune(r)
+			case r < 0:
+				// Second column of a wide character
+			case c.lines[y][x] != 0:
+				b.WriteRune(lineChars[c.lines[y][x]])
+			default:
+				b.WriteByte(' ')
+			}
+		}
+		lines[y] = strings.TrimRight(b.String(), " ")
+	}
+	return lines
+}
+
+// sign returns -1, 0 or 1 for negative, zero and positive numbers.
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// abs returns the absolute value of a number.
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,352 @@
+package unidoc
+
+import (
+	"cmp"
+	"encoding/csv"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// chartValue matches a value of a chart, a number with an optional unit such
+// as "120ms" or "12%".
+var chartValue = regexp.MustCompile(`^([-+]?(?:[0-9][0-9_]*)?\.?[0-9]+(?:[eE][-+]?[0-9]+)?)\s*(\S*)$`)
+
+// A chartSeries is a row of chart data: a label with its values.
+type chartSeries struct {
+	label  string
+	values []float64
+	text   string // Values as written, shown next to bars
+	unit   string // Unit of the first value with one, such as "ms"
+}
+
+// chartData holds the rows of a chart block together with the names from a
+// header row.
+type chartData struct {
+	series      []chartSeries
+	labelHeader string
+	valueHeader string
+	unit        string // Unit of the value axis, from the first series with one
+}
+
+// parseChartData parses chart data given as "label: value" lines or as CSV.
+// A first row without numbers is a header. Blank lines and lines starting
+// with # are skipped.
+func parseChartData(content string) (chartData, error) {
+	var data chartData
+	for i, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var fields []string
+		if label, values, ok := cutLast(line, ":"); ok {
+			fields = append([]string{label}, strings.FieldsFunc(values, func(r rune) bool {
+				return r == ',' || r == ' ' || r == '\t'
+			})...)
+		} else {
+			reader := csv.NewReader(strings.NewReader(line))
+			reader.TrimLeadingSpace = true
+			record, err := reader.Read()
+			if err != nil {
+				return data, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			fields = record
+		}
+
+		series := chartSeries{label: strings.TrimSpace(fields[0])}
+		var texts []string
+		for _, field := range fields[1:] {
+			field = strings.TrimSpace(field)
+			match := chartValue.FindStringSubmatch(field)
+			if match == nil {
+				break
+			}
+			value, err := strconv.ParseFloat(strings.ReplaceAll(match[1], "_", ""), 64)
+			if err != nil || math.IsInf(value, 0) {
+				break // Out of range, such as 1e400
+			}
+			series.values = append(series.values, value)
+			texts = append(texts, field)
+			series.unit = cmp.Or(series.unit, match[2])
+		}
+
+		switch {
+		case len(series.values) == len(fields)-1 && len(series.values) > 0:
+			series.text = strings.Join(texts, " ")
+			data.series = append(data.series, series)
+			data.unit = cmp.Or(data.unit, series.unit)
+		case len(data.series) == 0 && data.labelHeader == "" && len(series.values) == 0:
+			data.labelHeader = series.label
+			if len(fields) > 1 {
+				data.valueHeader = strings.TrimSpace(fields[1])
+			}
+		default:
+			return data, fmt.Errorf("line %d: invalid value: %s", i+1, line)
+		}
+	}
+	if len(data.series) == 0 {
+		return data, fmt.Errorf("no data")
+	}
+	return data, nil
+}
+
+// cutLast slices s around the last instance of sep.
+func cutLast(s, sep string) (before, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
+
+// formatChartValue formats a value of a chart with its unit, using an
+// exponent for values too large or too small to write out.
+func formatChartValue(value float64, unit string) string {
+	if abs := math.Abs(value); abs >= 1e21 || abs != 0 && abs < 1e-6 {
+		return strconv.FormatFloat(value, 'g', -1, 64) + unit
+	}
+	return strconv.FormatFloat(value, 'f', -1, 64) + unit
+}
+
+// scaleMax returns the largest value of a chart, or the max attribute.
+func scaleMax(data chartData, attrs map[string]string) (float64, error) {
+	if text, ok := attrs["max"]; ok {
+		limit, err := strconv.ParseFloat(text, 64)
+		if err != nil || math.IsNaN(limit) || math.IsInf(limit, 0) || limit <= 0 {
+			return 0, fmt.Errorf("invalid max: %s", text)
+		}
+		return limit, nil
+	}
+
+	limit := 0.0
+	for _, series := range data.series {
+		for _, value := range series.values {
+			limit = max(limit, value)
+		}
+	}
+	return limit, nil
+}
+
+// eighths returns the number of eighths of size that value fills, relative
+// to limit. Negative values fill nothing and values beyond limit fill size.
+func eighths(value, limit float64, size int) int {
+	if !(limit > 0) || !(value > 0) { // Also false for NaN
+		return 0
+	}
+	return int(math.Round(min(value/limit, 1) * float64(max(size, 0)*8)))
+}
+
+// horizontalBar returns a bar of a length in eighths of a column.
+func horizontalBar(eighths int) string {
+	partial := []string{"", "▏", "▎", "▍", "▌", "▋", "▊", "▉"}
+	return strings.Repeat("█", eighths/8) + partial[eighths%8]
+}
+
+// verticalBlocks holds the blocks filling a row of a column by eighths.
+var verticalBlocks = []string{" ", "▁", "▂", "▃", "▄", "▅", "▆", "▇", "█"}
+
+// chartProcessor renders ```chart blocks as bar charts, column charts or
+// sparklines, chosen by the type attribute:
+//
+//	```chart {type=column title="p99 latency" height=6}
+//	api: 120ms
+//	db: 8ms
+type chartProcessor struct{}
+
+// ProcessBlock implements BlockProcessor.ProcessBlock.
+func (p chartProcessor) ProcessBlock(block FencedBlock, width int) ([]string, error) {
+	data, err := parseChartData(block.Content)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnprocessed, err)
+	}
+
+	var lines []string
+	if title := cmp.Or(block.Attributes["title"], data.valueHeader); title != "" {
+		lines = append(lines, toBoldSansSerifText(truncateText(title, width)))
+	}
+
+	var chart []string
+	switch chartType := cmp.Or(block.Attributes["type"], "bar"); chartType {
+	case "bar":
+		chart, err = barChart(data, block.Attributes, width)
+	case "column":
+		chart, err = columnChart(data, block.Attributes, width)
+	case "sparkline":
+		chart = sparklines(data, width)
+	default:
+		return nil, fmt.Errorf("%w: invalid chart type: %s", ErrUnprocessed, chartType)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnprocessed, err)
+	}
+	return append(lines, chart...), nil
+}
+
+// singleValues checks that every series of a chart has a single value.
+func singleValues(data chartData) error {
+	for _, series := range data.series {
+		if len(series.values) != 1 {
+			return fmt.Errorf("expected a single value for %s", series.label)
+		}
+	}
+	return nil
+}
+
+// barChart lays out a horizontal bar chart with a labelled value axis:
+//
+//	api │████████████▍ 120ms
+//	db  │▊ 8ms
+//	    └─────────────
+//	     0        120ms
+func barChart(data chartData, attrs map[string]string, width int) ([]string, error) {
+	if err := singleValues(data); err != nil {
+		return nil, err
+	}
+	limit, err := scaleMax(data, attrs)
+	if err != nil {
+		return nil, err
+	}
+
+	labelWidth, textWidthMax := textWidth(data.labelHeader), 0
+	for _, series := range data.series {
+		labelWidth = max(labelWidth, textWidth(series.label))
+		textWidthMax = max(textWidthMax, textWidth(series.text))
+	}
+	labelWidth = min(labelWidth, width/3)
+	size := max(width-labelWidth-textWidthMax-3, 4)
+
+	var lines []string
+	for _, series := range data.series {
+		label := padRight(truncateText(series.label, labelWidth), labelWidth)
+		bar := horizontalBar(eighths(series.values[0], limit, size))
+		lines = append(lines, label+" │"+bar+" "+series.text)
+	}
+
+	// The value axis runs from 0 to the largest value
+	indent := strings.Repeat(" ", labelWidth+1)
+	top := formatChartValue(limit, data.unit)
+	lines = append(lines, padRight(truncateText(data.labelHeader, labelWidth), labelWidth)+" └"+strings.Repeat("─", size))
+	lines = append(lines, indent+" 0"+strings.Repeat(" ", max(size-1-textWidth(top), 1))+top)
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return lines, nil
+}
+
+// columnChart lays out a vertical column chart of the height attribute, 8
+// rows by default, with the labels below the columns:
+//
+//	120ms ┤ █
+//	      │ █
+//	    0 └────
+//	        api db
+func columnChart(data chartData, attrs map[string]string, width int) ([]string, error) {
+	if err := singleValues(data); err != nil {
+		return nil, err
+	}
+	limit, err := scaleMax(data, attrs)
+	if err != nil {
+		return nil, err
+	}
+
+	height := 8
+	if text, ok := attrs["height"]; ok {
+		if height, err = strconv.Atoi(text); err != nil || height < 1 {
+			return nil, fmt.Errorf("invalid height: %s", text)
+		}
+	}
+
+	top := formatChartValue(limit, data.unit)
+	axisWidth := max(textWidth(top), 1)
+	count := len(data.series)
+	column := 1
+	for _, series := range data.series {
+		column = max(column, textWidth(series.label))
+	}
+	column = max(min(column, (width-axisWidth-2)/count-1), 1)
+
+	var lines []string
+	for row := height - 1; row >= 0; row-- {
+		axis := strings.Repeat(" ", axisWidth) + " │"
+		if row == height-1 {
+			axis = padLeft(top, axisWidth) + " ┤"
+		}
+		cells := make([]string, count)
+		for i, series := range data.series {
+			level := min(max(eighths(series.values[0], limit, height)-row*8, 0), 8)
+			cells[i] = strings.Repeat(verticalBlocks[level], column)
+		}
+		lines = append(lines, axis+" "+strings.Join(cells, " "))
+	}
+	lines = append(lines, padLeft("0", axisWidth)+" └"+strings.Repeat("─", count*(column+1)))
+
+	labels := make([]string, count)
+	for i, series := range data.series {
+		labels[i] = centerText(truncateText(series.label, column), column)
+	}
+	lines = append(lines, strings.Repeat(" ", axisWidth+3)+strings.Join(labels, " "))
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return lines, nil
+}
+
+// padLeft pads a string with spaces on the left up to the given width.
+func padLeft(s string, width int) string {
+	return strings.Repeat(" ", max(width-textWidth(s), 0)) + s
+}
+
+// sparklines lays out a sparkline for every series, scaled between its own
+// lowest and highest value, followed by that range. Series with more values
+// than columns are averaged down to fit.
+func sparklines(data chartData, width int) []string {
+	labelWidth := 0
+	for _, series := range data.series {
+		labelWidth = max(labelWidth, textWidth(series.label))
+	}
+	labelWidth = min(labelWidth, width/3)
+
+	var lines []string
+	for _, series := range data.series {
+		low, high := series.values[0], series.values[0]
+		for _, value := range series.values {
+			low, high = min(low, value), max(high, value)
+		}
+		span := formatChartValue(low, series.unit) + "–" + formatChartValue(high, series.unit)
+
+		values := resample(series.values, max(width-labelWidth-textWidth(span)-2, 1))
+		var b strings.Builder
+		for _, value := range values {
+			level := 4
+			// Halves keep the differences of extreme values finite
+			if ratio := (value/2 - low/2) / (high/2 - low/2); high > low && !math.IsNaN(ratio) {
+				level = 1 + int(math.Round(min(max(ratio, 0), 1)*7))
+			}
+			b.WriteString(verticalBlocks[level])
+		}
+
+		label := padRight(truncateText(series.label, labelWidth), labelWidth)
+		lines = append(lines, strings.TrimLeft(label+" "+b.String()+" "+span, " "))
+	}
+	return lines
+}
+
+// resample averages values down to at most size values.
+func resample(values []float64, size int) []float64 {
+	if len(values) <= size {
+		return values
+	}
+	out := make([]float64, size)
+	for i := range out {
+		start, end := i*len(values)/size, (i+1)*len(values)/size
+		sum := 0.0
+		for _, value := range values[start:end] {
+			sum += value
+		}
+		out[i] = sum / float64(end-start)
+	}
+	return out
+}
@@ -0,0 +1,83 @@
+package unidoc
+
+import "testing"
+
+func TestChartProcessor(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    string
+		attributes map[string]string
+		want       string
+	}{
+		{"bar", "api: 120ms\ndb: 8ms\ncache: 1ms\n", nil, `
+api   │█████████████████ 120ms
+db    │█▏ 8ms
+cache │▏ 1ms
+      └─────────────────
+       0           120ms`},
+		{"csv header", "Service,Latency\napi,120\ndb,45\n", nil, `
+𝗟𝗮𝘁𝗲𝗻𝗰𝘆
+api     │█████████████████ 120
+db      │██████▍ 45
+Service └─────────────────
+         0             120`},
+		{"column", "api: 120ms\ndb: 8ms\n", map[string]string{"type": "column", "height": "4", "title": "p99"}, `
+𝗽𝟵𝟵
+120ms ┤ ███
+      │ ███
+      │ ███
+      │ ███ ▂▂▂
+    0 └────────
+        api db`},
+		{"sparkline", "cpu: 1 5 3 8 2 7\nmem: 4,4,5,6,6,7\n", map[string]string{"type": "sparkline"}, `
+cpu ▁▅▃█▂▇ 1–8
+mem ▁▁▃▆▆█ 4–7`},
+		{"max", "a: 5\nb: 10\n", map[string]string{"max": "20"}, `
+a │██████ 5
+b │████████████ 10
+  └────────────────────────
+   0                     20`},
+		{"zeros", "a: 0\nb: 0\n", nil, `
+a │ 0
+b │ 0
+  └─────────────────────────
+   0                       0`},
+		{"negative", "a: -3\nb: 2\n", nil, `
+a │ -3
+b │████████████████████████ 2
+  └────────────────────────
+   0                      2`},
+		{"huge", "a: 1.5e308\nb: 1.7e308\n", nil, `
+a │████████████████▊ 1.5e308
+b │███████████████████ 1.7e308
+  └───────────────────
+   0          1.7e+308`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := processTest(t, FencedBlock{Language: "chart", Content: tt.content, Attributes: tt.attributes}, 30)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkOutput(t, got, tt.want)
+		})
+	}
+}
+
+func TestEighths(t *testing.T) {
+	tests := []struct {
+		value, limit float64
+		size, want   int
+	}{
+		{50, 100, 10, 40},
+		{100, 100, 10, 80},
+		{200, 100, 10, 80},
+		{-1, 100, 10, 0},
+		{1, 0, 10, 0},
+	}
+	for _, tt := range tests {
+		if got := eighths(tt.value, tt.limit, tt.size); got != tt.want {
+			t.Errorf("eighths(%g, %g, %d) = %d, want %d", tt.value, tt.limit, tt.size, got, tt.want)
+		}
+	}
+}
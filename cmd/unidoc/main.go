@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/spf13/pflag"
 
@@ -20,6 +22,7 @@ lists, and more.
 
 Usage:
   unidoc [OPTION]... [FILE]
+  unidoc outline [OPTION]... [FILE]   print the heading tree with heading IDs
 
 Options:
 `)
@@ -38,13 +41,144 @@ Strong Styles:
   markers                     use simple markers around strong text: **text**
   bold-sans-serif             use mathematical bold sans-serif: 𝗧𝗵𝗶𝘀 𝗶𝘀 𝗯𝗼𝗹𝗱
 
+Highlight and Underline Styles:
+  plain                       use regular text, no special formatting
+  markers                     keep the markers around the text: ==text==, ++text++
+  underline                   use a combining low line: t̲e̲x̲t̲
+  double-underline            use a combining double low line: t̳e̳x̳t̳
+  brackets                    use lenticular brackets: 【text】
+
+Box Styles (fenced divs):
+  single                      single lines: ┌─┐
+  double                      double lines: ╔═╗
+  rounded                     rounded corners: ╭─╮
+  heavy                       heavy lines: ┏━┓
+
+Heading Options (--heading LEVEL:name=value,...):
+  prefix=GLYPH                glyph in front of the heading text
+  text=STYLE                  plain, bold-sans-serif, slanted-sans-serif, script,
+                              double-struck, uppercase or small-caps
+  underline=CHAR              character repeated below the heading, empty for none
+  length=LENGTH               underline length: text, full or none
+  frame=FRAME                 none, box (double-lined box) or banner (centered)
+  font=FONT                   large letters in a bitmap font: none, block (▀▄█),
+                              shadow (█░), small (3×5 pixels) or braille (⣿)
+
+Link Styles:
+  emoji                       brackets and a link emoji: [text] 🔗 <url>
+  inline                      the URL after the text: text <url>
+  text                        the link text only
+  footnote                    numbered references listed at the end: text¹
+  qr                          footnote references listed with QR codes to scan
+
+Image Styles (local PNG, JPEG and GIF files):
+  text                        the alt text and URL: 🖼️  alt <url>
+  blocks                      half blocks, 1×2 pixels per character
+  quadrants                   quadrant blocks, 2×2 pixels per character
+  braille                     braille patterns, 2×4 pixels per character
+
+Link Templates (--link-template KIND=URL, {id} and {repo} are replaced):
+  wiki                        [[Page Name]] wiki links
+  issue                       #123 issue references
+  repo-issue                  org/repo#45 cross-repository issue references
+  mention                     @user mentions
+
+Title Styles (front matter):
+  plain                       title, author, date and tags on plain lines
+  banner                      centered title between heavy rules: ━━━
+  box                         centered title in a double-lined box: ╔═╗
+
 Examples:
   echo '# Hello World' | unidoc
   unidoc README.md
   unidoc --italic script < document.md
+  unidoc --section "Release notes" --shift-headings -1 CHANGELOG.md
+  unidoc outline README.md
+  unidoc --heading 1:font=block,frame=banner MOTD.md
+  unidoc --link-template issue=https://tracker.example/{id} notes.md
+  unidoc --filter plantuml="plantuml -tutxt -pipe" --allow-filter plantuml design.md
 `)
 }
 
+// filterFlag collects the --filter flags as commands by language.
+type filterFlag map[string][]string
+
+// Set implements the pflag.Value interface for filterFlag. The value has the
+// form "LANGUAGE=COMMAND", where the command is split into arguments at
+// spaces outside of quotes.
+func (f *filterFlag) Set(value string) error {
+	language, command, ok := strings.Cut(value, "=")
+	if !ok || language == "" {
+		return fmt.Errorf("invalid filter: %s", value)
+	}
+	args, err := splitCommand(command)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("empty filter command: %s", language)
+	}
+	if *f == nil {
+		*f = make(filterFlag)
+	}
+	(*f)[language] = args
+	return nil
+}
+
+// String implements the pflag.Value interface for filterFlag.
+func (f *filterFlag) String() string {
+	return ""
+}
+
+// Type implements the pflag.Value interface for filterFlag.
+func (f *filterFlag) Type() string {
+	return "filter"
+}
+
+// splitCommand splits a command line into arguments like a shell does, at
+// spaces outside of single or double quotes. A backslash escapes the next
+// character outside of single quotes.
+func splitCommand(command string) ([]string, error) {
+	var (
+		args    []string
+		arg     strings.Builder
+		inArg   bool
+		quote   rune
+		escaped bool
+	)
+	for _, r := range command {
+		switch {
+		case escaped:
+			arg.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped, inArg = true, true
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			arg.WriteRune(r)
+		case r == '\'' || r == '"':
+			quote, inArg = r, true
+		case r == ' ' || r == '\t':
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		default:
+			arg.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 || escaped {
+		return nil, fmt.Errorf("unterminated quote or escape in command: %s", command)
+	}
+	if inArg {
+		args = append(args, arg.String())
+	}
+	return args, nil
+}
+
 func mainE() error {
 	config := unidoc.DefaultConfig()
 
@@ -54,6 +188,28 @@ func mainE() error {
 	)
 	pflag.Var(&config.ItalicStyle, "italic-style", "style for italic text")
 	pflag.Var(&config.StrongStyle, "strong-style", "style for strong text")
+	pflag.Var(&config.HighlightStyle, "highlight-style", "style for ==highlighted== text")
+	pflag.Var(&config.UnderlineStyle, "underline-style", "style for ++inserted++ text")
+	pflag.Var(&config.TitleStyle, "title-style", "style for the title block from front matter")
+	pflag.Var(&config.LinkStyle, "link-style", "style for links")
+	pflag.Var(&config.ImageStyle, "image-style", "style for images from local files")
+	pflag.IntVar(&config.ImageWidth, "image-width", config.ImageWidth, "width of drawn images in columns, 0 for the full width")
+	pflag.StringToStringVar(&config.LinkTemplates, "link-template", nil, "URL template for wiki links, issue references or mentions")
+	pflag.Var((*filterFlag)(&config.Filters), "filter", "external command for a fenced block language, such as plantuml=\"plantuml -tutxt -pipe\" (repeatable)")
+	pflag.StringSliceVar(&config.AllowedFilters, "allow-filter", nil, "program that filters may run, such as plantuml (repeatable)")
+	pflag.DurationVar(&config.FilterTimeout, "filter-timeout", config.FilterTimeout, "time limit of a filter command")
+	pflag.IntVar(&config.FilterMaxOutput, "filter-max-output", config.FilterMaxOutput, "limit of the output of a filter command in bytes")
+	pflag.StringVar(&config.Section, "section", "", "render only the section with this heading text")
+	pflag.StringVar(&config.SectionID, "section-id", "", "render only the section with this heading ID")
+	pflag.IntVar(&config.MaxHeadingLevel, "max-heading-level", 0, "drop sections with headings deeper than this level")
+	pflag.IntVar(&config.ShiftHeadings, "shift-headings", 0, "move all headings by this many levels, such as -1 or 2")
+	pflag.BoolVar(&config.IgnoreOverrides, "ignore-overrides", false, "ignore rendering options set by the document")
+	pflag.Var(&config.Headings, "heading", "heading style for a level, such as \"1:frame=box,prefix=\" (repeatable)")
+	pflag.BoolVar(&config.NumberHeadings, "number-headings", false, "prefix headings with section numbers")
+	pflag.StringSliceVar(&config.HeadingNumberFormats, "heading-numbers", nil, "section number formats by depth, such as \"{I}.,{I}.{1}\"")
+	pflag.BoolVar(&config.TOC, "toc", false, "add a table of contents")
+	pflag.IntVar(&config.TOCDepth, "toc-depth", config.TOCDepth, "number of heading levels in the table of contents, 0 for all")
+	pflag.IntVarP(&config.Width, "width", "w", config.Width, "target width of the output in columns")
 
 	pflag.Usage = showHelp
 	pflag.Parse()
@@ -64,15 +220,26 @@ func mainE() error {
 		return nil
 	}
 
+	// The outline subcommand comes before the file name
+	args := pflag.Args()
+	outline := len(args) > 0 && args[0] == "outline"
+	if outline {
+		args = args[1:]
+	}
+
 	var (
 		content  []byte
 		err      error
-		fileName = pflag.Arg(0) // Get the first positional argument as file name
+		fileName string
 	)
+	if len(args) > 0 {
+		fileName = args[0] // Get the first positional argument as file name
+	}
 
 	// Read from file if specified.
 	if fileName != "" && fileName != "-" {
 		content, err = os.ReadFile(fileName)
+		config.ImageDir = filepath.Dir(fileName) // Images are relative to the file
 	} else {
 		// Read from stdin.
 		fileName = "stdin"
@@ -83,7 +250,15 @@ func mainE() error {
 	}
 
 	content = bytes.TrimSpace(content)
-	if len(content) > 0 {
+	if len(content) > 0 && outline {
+		result, err := unidoc.Outline(content, config)
+		if err != nil {
+			return fmt.Errorf("outline error: %w", err)
+		}
+		if result != "" {
+			fmt.Println(result)
+		}
+	} else if len(content) > 0 {
 		result, err := unidoc.Convert(content, config)
 		if err != nil {
 			return fmt.Errorf("conversion error: %w", err)
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSplitCommand(t *testing.T) {
+	tests := []struct {
+		command string
+		args    []string
+	}{
+		{"plantuml -tutxt -pipe", []string{"plantuml", "-tutxt", "-pipe"}},
+		{"  spaced \t out  ", []string{"spaced", "out"}},
+		{`tool "two words" 'single "quoted"'`, []string{"tool", "two words", `single "quoted"`}},
+		{`tool a\ b "c\"d" 'e\f'`, []string{"tool", "a b", `c"d`, `e\f`}},
+		{`tool ""`, []string{"tool", ""}},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		args, err := splitCommand(tt.command)
+		if err != nil {
+			t.Errorf("splitCommand(%q): %v", tt.command, err)
+		} else if !slices.Equal(args, tt.args) {
+			t.Errorf("splitCommand(%q) = %q, want %q", tt.command, args, tt.args)
+		}
+	}
+
+	for _, command := range []string{`tool "open`, `tool 'open`, `tool \`} {
+		if _, err := splitCommand(command); err == nil {
+			t.Errorf("splitCommand(%q) accepted", command)
+		}
+	}
+}
+
+func TestFilterFlag(t *testing.T) {
+	var f filterFlag
+	if err := f.Set(`plantuml=plantuml -tutxt -pipe`); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := f["plantuml"], []string{"plantuml", "-tutxt", "-pipe"}; !slices.Equal(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	for _, value := range []string{"plantuml", "=cat", "empty=", `open=tool "x`} {
+		if err := f.Set(value); err == nil {
+			t.Errorf("Set(%q) accepted", value)
+		}
+	}
+}
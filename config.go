@@ -1,9 +1,61 @@
 package unidoc
 
-// Config holds the configuration for the Unicode renderer.
+import (
+	"maps"
+	"time"
+)
+
+// Config holds the configuration for the Unicode renderer. Copies of a Config
+// share its maps and slices, so methods that change them change copies, as
+// made by cloneMap.
 type Config struct {
 	ItalicStyle ItalicStyle // Style for italic text: "markers", "script", "sans-italic"
 	StrongStyle StrongStyle // Style for strong text: "plain", "markers", "math"
+	Width       int         // Target width of the output in columns
+
+	HighlightStyle HighlightStyle // Style for ==highlighted== text
+	UnderlineStyle UnderlineStyle // Style for ++inserted++ text
+
+	DivStyles map[string]BoxStyle // Box style for fenced divs, keyed by class name
+
+	BlockProcessors map[string]BlockProcessor // Renderers for fenced code blocks, keyed by lowercase language
+
+	// External commands for fenced code blocks by language as program and
+	// arguments, such as {"plantuml", "-tutxt", "-pipe"}, that run only if
+	// their program is allowed. Documents cannot set them.
+	Filters         map[string][]string
+	AllowedFilters  []string      // Programs that filters may run, such as "plantuml"
+	FilterTimeout   time.Duration // Time limit of a filter command
+	FilterMaxOutput int           // Limit of the output of a filter command in bytes
+
+	TitleStyle TitleStyle // Style for the title block from front matter
+	LinkStyle  LinkStyle  // Style for links
+
+	ImageStyle ImageStyle // Style for images, drawn from local files unless text; documents cannot set it
+	ImageWidth int        // Width of drawn images in columns, 0 for the full width
+	ImageDir   string     // Directory that image paths are resolved from and confined to
+
+	// URL templates for references by type: "wiki", "issue", "repo-issue"
+	// and "mention", such as "https://tracker.example/{id}"
+	LinkTemplates map[string]string
+
+	Bullets      []string      // Bullets for unordered lists, by nesting level, the defaults if empty
+	NumberStyles []NumberStyle // Numbering for ordered lists, by nesting level, the defaults if empty
+
+	Headings HeadingStyles // Heading styles by level, starting with level 1, the defaults if empty
+
+	NumberHeadings       bool     // Prefix headings with hierarchical section numbers
+	HeadingNumberFormats []string // Section number formats by depth, such as "{I}." or "{1}.{a}"
+
+	TOC      bool // Add a table of contents unless the document places one with [TOC]
+	TOCDepth int  // Number of heading levels in the table of contents, 0 for all
+
+	Section         string // Render only the section with this heading text
+	SectionID       string // Render only the section with this heading ID
+	MaxHeadingLevel int    // Drop sections with deeper headings, 0 for all
+	ShiftHeadings   int    // Move all headings by this many levels
+
+	IgnoreOverrides bool // Ignore rendering options set by the document itself
 }
 
 // DefaultConfig returns the default configuration for the Unicode renderer.
@@ -11,5 +63,77 @@ func DefaultConfig() Config {
 	return Config{
 		ItalicStyle: ItalicStyleSlantedSansSerif, // Default italic style
 		StrongStyle: StrongStyleBoldSansSerif,    // Default strong style
+		Width:       defaultWidth,
+
+		HighlightStyle: HighlightStyleBrackets,
+		UnderlineStyle: UnderlineStyleUnderline,
+
+		DivStyles: map[string]BoxStyle{
+			"note":      BoxStyleRounded,
+			"info":      BoxStyleRounded,
+			"tip":       BoxStyleRounded,
+			"summary":   BoxStyleRounded,
+			"sidebar":   BoxStyleSingle,
+			"warning":   BoxStyleHeavy,
+			"caution":   BoxStyleHeavy,
+			"important": BoxStyleDouble,
+			"danger":    BoxStyleDouble,
+		},
+
+		BlockProcessors: map[string]BlockProcessor{
+			"banner":   bannerProcessor{},
+			"chart":    chartProcessor{},
+			"csv":      csvProcessor{delimiter: ','},
+			"diff":     diffProcessor{},
+			"gantt":    ganttProcessor{},
+			"graph":    mermaidProcessor{},
+			"json":     structuredProcessor{parse: parseJSON},
+			"mermaid":  mermaidProcessor{},
+			"patch":    diffProcessor{},
+			"qr":       qrProcessor{},
+			"sequence": mermaidProcessor{},
+			"timeline": ganttProcessor{},
+			"tree":     treeProcessor{},
+			"tsv":      csvProcessor{delimiter: '\t'},
+			"yaml":     structuredProcessor{parse: parseYAML},
+			"yml":      structuredProcessor{parse: parseYAML},
+		},
+
+		FilterTimeout:   defaultFilterTimeout,
+		FilterMaxOutput: defaultFilterMaxOutput,
+
+		TitleStyle: TitleStyleBox,
+		LinkStyle:  LinkStyleEmoji,
+
+		ImageWidth: 40,
+
+		Bullets: []string{"•", "◦", "▪", "▫", "‣", "⁃"},
+		NumberStyles: []NumberStyle{
+			NumberStyleCircled,
+			NumberStyleParenthesized,
+			NumberStyleCircledLetter,
+			NumberStyleRoman,
+			NumberStyleUpperRoman,
+		},
+
+		Headings: HeadingStyles{
+			{Prefix: "█", Text: TextStyleBoldSansSerif, Underline: "═"},  // Full block for H1
+			{Prefix: "▓▓", Text: TextStyleBoldSansSerif, Underline: "─"}, // Medium shade for H2
+			{Prefix: "▒▒▒", Text: TextStyleBoldSansSerif},                // Light shade for H3
+			{Prefix: "░░░░", Text: TextStyleBoldSansSerif},               // Very light shade for H4
+			{Prefix: "▫", Text: TextStyleBoldSansSerif},                  // Small square for H5
+			{Prefix: "▪", Text: TextStyleBoldSansSerif},                  // Small black square for H6
+		},
+
+		TOCDepth: 3,
+	}
+}
+
+// cloneMap copies a map of a Config before it is changed, making a map in
+// place of nil.
+func cloneMap[M ~map[K]V, K comparable, V any](m M) M {
+	if m == nil {
+		return make(M)
 	}
+	return maps.Clone(m)
 }
@@ -0,0 +1,55 @@
+package unidoc
+
+import (
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// csvProcessor renders ```csv and ```tsv blocks as tables. The attributes
+// delimiter (a character or "tab") and header (true by default) adjust the
+// parsing:
+//
+//	```csv {delimiter=";" header=false}
+type csvProcessor struct {
+	delimiter rune // Default field delimiter
+}
+
+// ProcessBlock implements BlockProcessor.ProcessBlock.
+func (p csvProcessor) ProcessBlock(block FencedBlock, width int) ([]string, error) {
+	reader := csv.NewReader(strings.NewReader(block.Content))
+	reader.Comma = p.delimiter
+	reader.FieldsPerRecord = -1 // Short rows get empty cells
+	reader.TrimLeadingSpace = p.delimiter != '\t'
+	reader.LazyQuotes = p.delimiter == '\t'
+
+	if delimiter, ok := block.Attributes["delimiter"]; ok {
+		switch {
+		case delimiter == "tab" || delimiter == `\t`:
+			reader.Comma = '\t'
+		case utf8.RuneCountInString(delimiter) == 1:
+			reader.Comma, _ = utf8.DecodeRuneInString(delimiter)
+		default:
+			return nil, fmt.Errorf("%w: invalid delimiter: %s", ErrUnprocessed, delimiter)
+		}
+	}
+
+	header := true
+	if value, ok := block.Attributes["header"]; ok {
+		var err error
+		if header, err = strconv.ParseBool(value); err != nil {
+			return nil, fmt.Errorf("%w: invalid header: %s", ErrUnprocessed, value)
+		}
+	}
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnprocessed, err)
+	}
+	if len(rows) == 0 {
+		return nil, ErrUnprocessed
+	}
+	return drawTable(rows, header, width), nil
+}
@@ -0,0 +1,67 @@
+package unidoc
+
+import "testing"
+
+func TestCSVProcessor(t *testing.T) {
+	tests := []struct {
+		name  string
+		block FencedBlock
+		width int
+		want  string
+	}{
+		{"header and numbers", FencedBlock{Language: "csv", Content: "Name,Qty,Price\nApple,3,1.20\nBanana,12,0.5\n"}, 40, `
+┌────────┬─────┬───────┐
+│ 𝗡𝗮𝗺𝗲   │ 𝗤𝘁𝘆 │ 𝗣𝗿𝗶𝗰𝗲 │
+├────────┼─────┼───────┤
+│ Apple  │   3 │  1.20 │
+│ Banana │  12 │   0.5 │
+└────────┴─────┴───────┘`},
+		{"attributes", FencedBlock{Language: "csv", Content: "a;b\n1;2\n", Attributes: map[string]string{"delimiter": ";", "header": "false"}}, 40, `
+┌───┬───┐
+│ a │ b │
+│ 1 │ 2 │
+└───┴───┘`},
+		{"tsv", FencedBlock{Language: "tsv", Content: "x\ty\n\"q\t2\n"}, 40, `
+┌─────┬───┐
+│ 𝘅   │ 𝘆 │
+├─────┼───┤
+│ q 2 │   │
+└─────┴───┘`},
+		{"short rows", FencedBlock{Language: "csv", Content: "k,v\nshort\n"}, 40, `
+┌───────┬───┐
+│ 𝗸     │ 𝘃 │
+├───────┼───┤
+│ short │   │
+└───────┴───┘`},
+		{"line breaks", FencedBlock{Language: "csv", Content: "a,b\n\"line1\nline2\",x\n"}, 40, `
+┌─────────────┬───┐
+│ 𝗮           │ 𝗯 │
+├─────────────┼───┤
+│ line1 line2 │ x │
+└─────────────┴───┘`},
+		{"wrapped", FencedBlock{Language: "csv", Content: "Name,Description\nA,A long description that will not fit\n"}, 24, `
+┌──────┬───────────────┐
+│ 𝗡𝗮𝗺𝗲 │ 𝗗𝗲𝘀𝗰𝗿𝗶𝗽𝘁𝗶𝗼𝗻   │
+├──────┼───────────────┤
+│ A    │ A long        │
+│      │ description   │
+│      │ that will not │
+│      │ fit           │
+└──────┴───────────────┘`},
+		{"wide characters", FencedBlock{Language: "csv", Content: "Wide,日本語\n1,2\n"}, 40, `
+┌──────┬────────┐
+│ 𝗪𝗶𝗱𝗲 │ 日本語 │
+├──────┼────────┤
+│    1 │      2 │
+└──────┴────────┘`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := processTest(t, tt.block, tt.width)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkOutput(t, got, tt.want)
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package unidoc
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// hunkHeader matches the header of a hunk of a unified diff and captures the
+// first old and new line numbers and line counts.
+var hunkHeader = regexp.MustCompile(`^@@+ -([0-9]+)(?:,([0-9]+))? \+([0-9]+)(?:,([0-9]+))? @@+`)
+
+// hunkRange returns the first line number and the line count of a range of
+// a hunk header, where the count defaults to 1.
+func hunkRange(start, count string) (int, int) {
+	first, _ := strconv.Atoi(start)
+	lines := 1
+	if count != "" {
+		lines, _ = strconv.Atoi(count)
+	}
+	return first, lines
+}
+
+// diffFileName returns the name of a file from a ---/+++ header line,
+// without the a/ and b/ prefixes of git.
+func diffFileName(line string) string {
+	name, _, _ := strings.Cut(line[4:], "\t")
+	if name == "/dev/null" {
+		return ""
+	}
+	if strings.HasPrefix(name, "a/") || strings.HasPrefix(name, "b/") {
+		name = name[2:]
+	}
+	return name
+}
+
+// diffProcessor renders ```diff and ```patch blocks of unified diffs in a code
+// box with a gutter of +, − and context markers. Hunk headers and file names
+// become separators. The attribute line-numbers adds the old and new line
+// numbers from the hunk headers, and strike=false leaves removed lines
+// without a strikethrough:
+//
+//	```diff {line-numbers=true}
+type diffProcessor struct{}
+
+// ProcessBlock implements BlockProcessor.ProcessBlock.
+func (p diffProcessor) ProcessBlock(block FencedBlock, width int) ([]string, error) {
+	numbers, strike := false, true
+	for name, field := range map[string]*bool{"line-numbers": &numbers, "strike": &strike} {
+		if text, ok := block.Attributes[name]; ok {
+			value, err := strconv.ParseBool(text)
+			if err != nil {
+				return nil, fmt.Errorf("%w: invalid %s: %s", ErrUnprocessed, name, text)
+			}
+			*field = value
+		}
+	}
+
+	lines := strings.Split(strings.TrimRight(block.Content, "\n"), "\n")
+
+	// Line numbers take as many columns as the largest one
+	last, hunks := 0, false
+	for _, line := range lines {
+		if match := hunkHeader.FindStringSubmatch(line); match != nil {
+			oldStart, oldCount := hunkRange(match[1], match[2])
+			newStart, newCount := hunkRange(match[3], match[4])
+			last = max(last, oldStart+oldCount, newStart+newCount)
+			hunks = true
+		}
+	}
+	digits := 0
+	if numbers && hunks {
+		digits = len(strconv.Itoa(last))
+	}
+
+	// Boxes too narrow for a column of text are left to the code box
+	inner := width - 2
+	gutterWidth := 0
+	if digits > 0 {
+		gutterWidth = 2*digits + 2
+	}
+	if inner-gutterWidth-4 < 1 {
+		return nil, ErrUnprocessed
+	}
+	separator := func(title string) string {
+		title = truncateText(title, inner-4)
+		return "├─ " + title + " " + strings.Repeat("─", max(inner-3-textWidth(title), 0)) + "┤"
+	}
+
+	out := []string{"┌" + strings.Repeat("─", inner) + "┐"}
+	var (
+		oldLine, newLine int
+		oldLeft, newLeft int // Lines of the current hunk still to come
+		oldName, newName string
+	)
+	if !hunks {
+		// Snippets without hunk headers are a single hunk
+		oldLeft, newLeft = len(lines), len(lines)
+	}
+	for _, line := range lines {
+		if match := hunkHeader.FindStringSubmatch(line); match != nil {
+			oldLine, oldLeft = hunkRange(match[1], match[2])
+			newLine, newLeft = hunkRange(match[3], match[4])
+			out = append(out, separator(line))
+			continue
+		}
+
+		// Lines outside of hunks belong to the headers of files
+		if oldLeft <= 0 && newLeft <= 0 && !strings.HasPrefix(line, "\\") {
+			switch {
+			case strings.HasPrefix(line, "--- "):
+				oldName = diffFileName(line)
+			case strings.HasPrefix(line, "+++ "):
+				newName = diffFileName(line)
+				out = append(out, separator(toBoldSansSerifText(cmp.Or(newName, oldName))))
+			}
+			continue
+		}
+
+		// Context lines start with a space, or are empty when trimmed. Other
+		// lines of snippets keep their first character
+		marker, text := " ", line
+		if line != "" && strings.ContainsRune(" +-\\", rune(line[0])) {
+			text = line[1:]
+			switch line[0] {
+			case '+':
+				marker = "+"
+			case '-':
+				marker = "−"
+			case '\\':
+				marker, text = "\\", strings.TrimSpace(text) // No newline at end of file
+			}
+		}
+		if marker != "+" && marker != "\\" {
+			oldLeft--
+		}
+		if marker != "−" && marker != "\\" {
+			newLeft--
+		}
+
+		var gutter string
+		if digits > 0 {
+			oldNumber, newNumber := strings.Repeat(" ", digits), strings.Repeat(" ", digits)
+			if marker != "+" && marker != "\\" {
+				oldNumber = padLeft(strconv.Itoa(oldLine), digits)
+				oldLine++
+			}
+			if marker != "−" && marker != "\\" {
+				newNumber = padLeft(strconv.Itoa(newLine), digits)
+				newLine++
+			}
+			gutter = oldNumber + " " + newNumber + " "
+		}
+
+		room := inner - gutterWidth - 4
+		text = truncateText(strings.ReplaceAll(text, "\t", "    "), room)
+		if marker == "−" && strike {
+			// Strike through the text but not the indentation
+			code := strings.TrimLeft(text, " ")
+			text = text[:len(text)-len(code)] + combineText(code, '\u0336') // Combining long stroke overlay
+		}
+		cell := padRight(text, room)
+		out = append(out, "│ "+gutter+marker+" "+cell+" │")
+	}
+	return append(out, "└"+strings.Repeat("─", inner)+"┘"), nil
+}
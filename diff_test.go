@@ -0,0 +1,121 @@
+package unidoc
+
+import "testing"
+
+func TestHunkRange(t *testing.T) {
+	tests := []struct {
+		start, count string
+		first, lines int
+	}{
+		{"1", "3", 1, 3},
+		{"98", "", 98, 1},
+		{"0", "0", 0, 0},
+	}
+	for _, tt := range tests {
+		first, lines := hunkRange(tt.start, tt.count)
+		if first != tt.first || lines != tt.lines {
+			t.Errorf("hunkRange(%q, %q) = %d, %d, want %d, %d", tt.start, tt.count, first, lines, tt.first, tt.lines)
+		}
+	}
+}
+
+func TestDiffFileName(t *testing.T) {
+	tests := []struct {
+		line, name string
+	}{
+		{"--- a/x.go", "x.go"},
+		{"+++ b/dir/y.go", "dir/y.go"},
+		{"--- /dev/null", ""},
+		{"+++ plain.txt\t2024-01-01 00:00:00", "plain.txt"},
+	}
+	for _, tt := range tests {
+		if name := diffFileName(tt.line); name != tt.name {
+			t.Errorf("diffFileName(%q) = %q, want %q", tt.line, name, tt.name)
+		}
+	}
+}
+
+const diffSample = `diff --git a/x.go b/x.go
+--- a/x.go
++++ b/x.go
+@@ -1,3 +1,3 @@ func main() {
+ a := 1
+-b := 2
++b := 3
+ c
+\ No newline at end of file
+`
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name, content string
+		attrs         map[string]string
+		width         int
+		want          string
+	}{
+		{"file", diffSample, nil, 40, `
+┌──────────────────────────────────────┐
+├─ 𝘅.𝗴𝗼 ───────────────────────────────┤
+├─ @@ -1,3 +1,3 @@ func main() { ──────┤
+│   a := 1                             │
+│ − b̶ ̶:̶=̶ ̶2̶                             │
+│ + b := 3                             │
+│   c                                  │
+│ \ No newline at end of file          │
+└──────────────────────────────────────┘`},
+		{"line numbers without strike", diffSample, map[string]string{"line-numbers": "true", "strike": "false"}, 40, `
+┌──────────────────────────────────────┐
+├─ 𝘅.𝗴𝗼 ───────────────────────────────┤
+├─ @@ -1,3 +1,3 @@ func main() { ──────┤
+│ 1 1   a := 1                         │
+│ 2   − b := 2                         │
+│   2 + b := 3                         │
+│ 3 3   c                              │
+│     \ No newline at end of file      │
+└──────────────────────────────────────┘`},
+		{"no headers", "-old\n+new\n context\n", nil, 30, `
+┌────────────────────────────┐
+│ − o̶l̶d̶                      │
+│ + new                      │
+│   context                  │
+└────────────────────────────┘`},
+		{"wide line numbers", "@@ -98,3 +98,4 @@\n a\n-b\n+c\n+d\n e\n", map[string]string{"line-numbers": "true"}, 30, `
+┌────────────────────────────┐
+├─ @@ -98,3 +98,4 @@ ────────┤
+│  98  98   a                │
+│  99     − b̶                │
+│      99 + c                │
+│     100 + d                │
+│ 100 101   e                │
+└────────────────────────────┘`},
+		{"new file", "--- /dev/null\n+++ b/new.txt\n@@ -0,0 +1 @@\n+hello\n", nil, 30, `
+┌────────────────────────────┐
+├─ 𝗻𝗲𝘄.𝘁𝘅𝘁 ──────────────────┤
+├─ @@ -0,0 +1 @@ ────────────┤
+│ + hello                    │
+└────────────────────────────┘`},
+		{"truncated", "+a very long added line that will not fit in the box\n", nil, 24, `
+┌──────────────────────┐
+│ + a very long added… │
+└──────────────────────┘`},
+		{"unmarked line", "élan\n+new\n", nil, 30, `
+┌────────────────────────────┐
+│   élan                     │
+│ + new                      │
+└────────────────────────────┘`},
+		{"narrowest", "élan\n+new\n", nil, 7, `
+┌─────┐
+│   … │
+│ + … │
+└─────┘`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := processTest(t, FencedBlock{Language: "diff", Content: tt.content, Attributes: tt.attrs}, tt.width)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkOutput(t, got, tt.want)
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package unidoc
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// A FencedDiv represents a Pandoc-style fenced div container:
+//
+//	::: warning Title
+//	Content
+//	:::
+type FencedDiv struct {
+	gast.BaseBlock
+	Class string // First class of the div, selects the box style
+	Title string // Optional title shown in the top border
+}
+
+// KindFencedDiv is a NodeKind of the FencedDiv node.
+var KindFencedDiv = gast.NewNodeKind("FencedDiv")
+
+// Kind implements Node.Kind.
+func (n *FencedDiv) Kind() gast.NodeKind {
+	return KindFencedDiv
+}
+
+// Dump implements Node.Dump.
+func (n *FencedDiv) Dump(source []byte, level int) {
+	gast.DumpHelper(n, source, level, map[string]string{
+		"Class": n.Class,
+		"Title": n.Title,
+	}, nil)
+}
+
+// fencedDivStackKey holds the stack of fenced divs that are still open, so
+// that a closing fence is attributed to the innermost one.
+var fencedDivStackKey = parser.NewContextKey()
+
+type fencedDivParser struct{}
+
+// newFencedDivParser returns a new BlockParser that parses fenced divs.
+func newFencedDivParser() parser.BlockParser {
+	return &fencedDivParser{}
+}
+
+// fenceLength returns the number of colons starting a fence line and the
+// remainder of the line after them.
+func fenceLength(line []byte, pos int) (int, []byte) {
+	i := pos
+	for ; i < len(line) && line[i] == ':'; i++ {
+	}
+	return i - pos, line[i:]
+}
+
+// parseDivInfo splits the text after an opening fence into class and title.
+// Both "warning Title" and "{.warning} Title" are accepted.
+func parseDivInfo(info string) (class, title string) {
+	info = strings.TrimSpace(strings.TrimRight(strings.TrimSpace(info), ":"))
+
+	if strings.HasPrefix(info, "{") {
+		attrs, rest, _ := strings.Cut(info[1:], "}")
+		for _, attr := range strings.Fields(attrs) {
+			if strings.HasPrefix(attr, ".") {
+				class = attr[1:]
+				break
+			}
+		}
+		return class, strings.TrimSpace(rest)
+	}
+
+	class, title, _ = strings.Cut(info, " ")
+	return class, strings.TrimSpace(title)
+}
+
+func (b *fencedDivParser) Trigger() []byte {
+	return []byte{':'}
+}
+
+func (b *fencedDivParser) Open(_ gast.Node, reader text.Reader, pc parser.Context) (gast.Node, parser.State) {
+	line, segment := reader.PeekLine()
+	pos := pc.BlockOffset()
+	if pos < 0 || line[pos] != ':' {
+		return nil, parser.NoChildren
+	}
+
+	length, rest := fenceLength(line, pos)
+	if length < 3 || util.IsBlank(rest) {
+		// A bare fence closes a div and never opens one
+		return nil, parser.NoChildren
+	}
+
+	class, title := parseDivInfo(string(rest))
+	node := &FencedDiv{Class: class, Title: title}
+
+	stack, _ := pc.Get(fencedDivStackKey).([]*FencedDiv)
+	pc.Set(fencedDivStackKey, append(stack, node))
+
+	reader.Advance(segment.Len() - trailingNewline(line))
+	return node, parser.HasChildren
+}
+
+func (b *fencedDivParser) Continue(node gast.Node, reader text.Reader, pc parser.Context) parser.State {
+	line, segment := reader.PeekLine()
+
+	w, pos := util.IndentWidth(line, reader.LineOffset())
+	if w < 4 {
+		if length, rest := fenceLength(line, pos); length >= 3 && util.IsBlank(rest) {
+			// Only the innermost open div is closed by a bare fence
+			stack, _ := pc.Get(fencedDivStackKey).([]*FencedDiv)
+			if len(stack) > 0 && gast.Node(stack[len(stack)-1]) == node {
+				reader.Advance(segment.Stop - segment.Start - trailingNewline(line) + segment.Padding)
+				return parser.Close
+			}
+		}
+	}
+
+	return parser.Continue | parser.HasChildren
+}
+
+func (b *fencedDivParser) Close(node gast.Node, _ text.Reader, pc parser.Context) {
+	stack, _ := pc.Get(fencedDivStackKey).([]*FencedDiv)
+	stack = slices.DeleteFunc(stack, func(div *FencedDiv) bool {
+		return gast.Node(div) == node
+	})
+	pc.Set(fencedDivStackKey, stack)
+}
+
+func (b *fencedDivParser) CanInterruptParagraph() bool {
+	return true
+}
+
+func (b *fencedDivParser) CanAcceptIndentedLine() bool {
+	return false
+}
+
+// trailingNewline returns 1 if the line ends with a newline character.
+func trailingNewline(line []byte) int {
+	if bytes.HasSuffix(line, []byte("\n")) {
+		return 1
+	}
+	return 0
+}
+
+// divBoxStyle returns the box style for a fenced div class.
+func (r *UnicodeRenderer) divBoxStyle(class string) BoxStyle {
+	if style, ok := r.config.DivStyles[class]; ok {
+		return style
+	}
+
+	// Allow using a box style name directly as the class
+	var style BoxStyle
+	if err := style.UnmarshalText([]byte(class)); err == nil {
+		return style
+	}
+	return BoxStyleSingle
+}
+
+// FencedDiv renderer
+func (r *UnicodeRenderer) renderFencedDiv(
+	w util.BufWriter,
+	source []byte,
+	node gast.Node,
+	entering bool,
+) (gast.WalkStatus, error) {
+	if !entering {
+		return gast.WalkContinue, nil
+	}
+
+	n := node.(*FencedDiv)
+	width := r.width
+	inner := width - 4
+
+	content, err := r.renderChildren(source, n, inner)
+	if err != nil {
+		return gast.WalkStop, err
+	}
+
+	var title string
+	if n.Title != "" {
+		title = r.strongText(r.toSmartDashes(n.Title))
+	}
+
+	box := drawBox(reflowText(content, inner), width, title, r.divBoxStyle(n.Class))
+	if _, err := w.WriteString(box + "\n"); err != nil {
+		return gast.WalkStop, err
+	}
+
+	return gast.WalkSkipChildren, nil
+}
@@ -0,0 +1,67 @@
+package unidoc
+
+import "testing"
+
+func TestParseDivInfo(t *testing.T) {
+	tests := []struct {
+		info, class, title string
+	}{
+		{"note", "note", ""},
+		{" warning Read first ", "warning", "Read first"},
+		{"{.tip #id} Outer", "tip", "Outer"},
+		{"{#id} Title", "", "Title"},
+		{"danger :::", "danger", ""},
+	}
+	for _, tt := range tests {
+		class, title := parseDivInfo(tt.info)
+		if class != tt.class || title != tt.title {
+			t.Errorf("parseDivInfo(%q) = %q, %q, want %q, %q", tt.info, class, title, tt.class, tt.title)
+		}
+	}
+}
+
+func TestFencedDiv(t *testing.T) {
+	tests := []struct {
+		name, input, want string
+	}{
+		{"class style", "::: note\nRemember this.\n:::", `
+╭────────────────────────────╮
+│ Remember this.             │
+╰────────────────────────────╯`},
+		{"title", "::: warning Read first\nMind the *gap*.\n:::", `
+┏━ 𝗥𝗲𝗮𝗱 𝗳𝗶𝗿𝘀𝘁 ━━━━━━━━━━━━━━━┓
+┃ Mind the 𝘨𝘢𝘱.              ┃
+┗━━━━━━━━━━━━━━━━━━━━━━━━━━━━┛`},
+		{"nested", ":::: {.tip} Outer\nText\n\n::: danger\nInner\n:::\n::::", `
+╭─ 𝗢𝘂𝘁𝗲𝗿 ────────────────────╮
+│ Text                       │
+│                            │
+│ ╔════════════════════════╗ │
+│ ║ Inner                  ║ │
+│ ╚════════════════════════╝ │
+╰────────────────────────────╯`},
+		{"unknown class", "::: custom\nPlain box\n:::", `
+┌────────────────────────────┐
+│ Plain box                  │
+└────────────────────────────┘`},
+		{"unclosed", "::: note\nNever closed", `
+╭────────────────────────────╮
+│ Never closed               │
+╰────────────────────────────╯`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkOutput(t, convertTest(t, tt.input), tt.want)
+		})
+	}
+}
+
+func TestFencedDivStyles(t *testing.T) {
+	got := convertTest(t, "::: custom\nText\n:::", func(c *Config) {
+		c.DivStyles = map[string]BoxStyle{"custom": BoxStyleDouble}
+	})
+	checkOutput(t, got, `
+╔════════════════════════════╗
+║ Text                       ║
+╚════════════════════════════╝`)
+}
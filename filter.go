@@ -0,0 +1,99 @@
+package unidoc
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limits of CommandFilter when its own are zero.
+const (
+	defaultFilterTimeout   = 10 * time.Second
+	defaultFilterMaxOutput = 1 << 20
+)
+
+// errFilterOutput stops a filter that writes more than its output limit.
+var errFilterOutput = errors.New("output limit exceeded")
+
+// A CommandFilter is a BlockProcessor that runs an external command with the
+// content of a block on its standard input and shows the standard output in
+// place of the block, such as plantuml -tutxt -pipe. The command runs without
+// a shell, with the output width in the UNIDOC_WIDTH environment variable.
+// Commands that fail, run out of time or write too much leave the block to
+// the boxed code rendering.
+type CommandFilter struct {
+	Command   []string      // Program and arguments
+	Timeout   time.Duration // Time limit of the command, 10 seconds if zero
+	MaxOutput int           // Limit of the output in bytes, 1 MiB if zero
+}
+
+// ProcessBlock implements BlockProcessor.ProcessBlock.
+func (f CommandFilter) ProcessBlock(block FencedBlock, width int) ([]string, error) {
+	if len(f.Command) == 0 {
+		return nil, ErrUnprocessed
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), cmp.Or(f.Timeout, defaultFilterTimeout))
+	defer cancel()
+
+	output := &limitedBuffer{limit: cmp.Or(f.MaxOutput, defaultFilterMaxOutput)}
+	cmd := exec.CommandContext(ctx, f.Command[0], f.Command[1:]...)
+	cmd.Stdin = strings.NewReader(block.Content)
+	cmd.Stdout = output
+	cmd.Env = append(os.Environ(), "UNIDOC_WIDTH="+strconv.Itoa(width))
+	cmd.WaitDelay = time.Second // Children keeping the output open are cut off
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnprocessed, err)
+	}
+
+	text := strings.TrimRight(strings.ReplaceAll(output.String(), "\r\n", "\n"), "\n")
+	if strings.TrimSpace(text) == "" {
+		return nil, ErrUnprocessed
+	}
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = truncateText(strings.TrimRight(line, " \t"), width)
+	}
+	return lines, nil
+}
+
+// A limitedBuffer collects output up to a limit in bytes, failing writes
+// beyond it.
+type limitedBuffer struct {
+	strings.Builder
+	limit int
+}
+
+// Write implements io.Writer.Write.
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if b.Len()+len(p) > b.limit {
+		return 0, errFilterOutput
+	}
+	return b.Builder.Write(p)
+}
+
+// registerFilters registers a CommandFilter for every filter whose program is
+// allowed. Filters for other programs are left out, so their blocks keep the
+// boxed code rendering.
+func (c *Config) registerFilters() error {
+	for language, command := range c.Filters {
+		if len(command) == 0 || command[0] == "" {
+			return fmt.Errorf("empty filter command: %s", language)
+		}
+		if !slices.Contains(c.AllowedFilters, command[0]) {
+			continue
+		}
+		c.RegisterBlockProcessor(language, CommandFilter{
+			Command:   slices.Clone(command),
+			Timeout:   c.FilterTimeout,
+			MaxOutput: c.FilterMaxOutput,
+		})
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package unidoc
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+// requirePrograms skips a test unless the programs it runs are installed.
+func requirePrograms(t *testing.T, programs ...string) {
+	t.Helper()
+	for _, program := range programs {
+		if _, err := exec.LookPath(program); err != nil {
+			t.Skipf("%s not installed", program)
+		}
+	}
+}
+
+func TestCommandFilter(t *testing.T) {
+	requirePrograms(t, "tr", "printenv")
+	tests := []struct {
+		name    string
+		command []string
+		content string
+		want    string
+	}{
+		{"output", []string{"tr", "a-z", "A-Z"}, "hello\nworld\n", `
+HELLO
+WORLD`},
+		{"truncated", []string{"tr", "a-z", "A-Z"}, "a line longer than the width\n", `
+A LINE LONGER THAN THE…`},
+		{"width", []string{"printenv", "UNIDOC_WIDTH"}, "", `
+23`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines, err := CommandFilter{Command: tt.command}.ProcessBlock(FencedBlock{Content: tt.content}, 23)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkOutput(t, strings.Join(lines, "\n"), tt.want)
+		})
+	}
+}
+
+func TestCommandFilterUnprocessed(t *testing.T) {
+	requirePrograms(t, "false", "sleep", "yes", "true")
+	tests := []struct {
+		name   string
+		filter CommandFilter
+	}{
+		{"no command", CommandFilter{}},
+		{"missing program", CommandFilter{Command: []string{"unidoc-no-such-program"}}},
+		{"failure", CommandFilter{Command: []string{"false"}}},
+		{"no output", CommandFilter{Command: []string{"true"}}},
+		{"timeout", CommandFilter{Command: []string{"sleep", "10"}, Timeout: 50 * time.Millisecond}},
+		{"output limit", CommandFilter{Command: []string{"yes"}, MaxOutput: 1000}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.filter.ProcessBlock(FencedBlock{Content: "x\n"}, 30)
+			if !errors.Is(err, ErrUnprocessed) {
+				t.Errorf("got error %v, want ErrUnprocessed", err)
+			}
+		})
+	}
+}
+
+func TestRegisterFilters(t *testing.T) {
+	requirePrograms(t, "tr")
+	input := "```shout\nhello\n```\n\n```quiet\nhello\n```"
+	got := convertTest(t, input, func(c *Config) {
+		c.Filters = map[string][]string{"shout": {"tr", "a-z", "A-Z"}, "quiet": {"cat"}}
+		c.AllowedFilters = []string{"tr"}
+	})
+	if !strings.Contains(got, "HELLO") {
+		t.Errorf("allowed filter did not run:\n%s", got)
+	}
+	if !strings.Contains(got, "│ hello") {
+		t.Errorf("filter that is not allowed did not keep the code box:\n%s", got)
+	}
+
+	config := DefaultConfig()
+	config.Filters = map[string][]string{"empty": {""}}
+	config.AllowedFilters = []string{""}
+	if _, err := Convert([]byte(input), config); err == nil {
+		t.Error("empty filter command accepted")
+	}
+}
+
+func TestFiltersFromDocument(t *testing.T) {
+	requirePrograms(t, "tr")
+	input := "---\nunidoc:\n  filters:\n    shout: tr a-z A-Z\n  allowed-filters: [tr]\n---\n\n```shout\nhello\n```"
+	if got := convertTest(t, input); strings.Contains(got, "HELLO") {
+		t.Errorf("document registered a filter:\n%s", got)
+	}
+}
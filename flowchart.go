@@ -0,0 +1,704 @@
+package unidoc
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// flowShape is the shape of the box of a flowchart node.
+type flowShape int
+
+const (
+	flowRect flowShape = iota
+	flowRound
+	flowDecision
+)
+
+// flowShapes lists the brackets around the text of a node and the shapes they
+// make, the doubled brackets first.
+var flowShapes = []struct {
+	open, close string
+	shape       flowShape
+}{
+	{"((", "))", flowRound},
+	{"([", "])", flowRound},
+	{"[[", "]]", flowRect},
+	{"[(", ")]", flowRound},
+	{"{{", "}}", flowDecision},
+	{"[", "]", flowRect},
+	{"(", ")", flowRound},
+	{"{", "}", flowDecision},
+}
+
+var (
+	// flowHeader matches the first line of a flowchart and captures the
+	// direction.
+	flowHeader = regexp.MustCompile(`^(?:graph|flowchart)(?:\s+(\w+))?$`)
+
+	// flowNodeID matches the ID of a node.
+	flowNodeID = regexp.MustCompile(`^\w+`)
+
+	// flowLink matches a link between nodes, such as --> or ---.
+	flowLink = regexp.MustCompile(`^(?:-{2,}>|-{3,}|={2,}>|={3,}|-\.+->|-\.+-)`)
+
+	// flowLinkLabel matches the label after a link: -->|label|.
+	flowLinkLabel = regexp.MustCompile(`^\s*\|([^|]*)\|`)
+
+	// flowLabeledLink matches a link with the label inside: -- label -->.
+	flowLabeledLink = regexp.MustCompile(`^(?:--|==|-\.)\s+(.+?)\s+(-{2,}>|-{3,}|={2,}>|={3,}|\.+->|\.+-)`)
+
+	// flowIgnored matches the statements that do not change the layout, such
+	// as styles and the lines around subgraphs.
+	flowIgnored = regexp.MustCompile(`^(?:subgraph|end|classDef|class|style|linkStyle|click|direction)\b`)
+)
+
+// A flowNode is a node of a flowchart, or a dummy node where an edge crosses
+// a layer of the layout.
+type flowNode struct {
+	label  string
+	shape  flowShape
+	dummy  bool
+	loop   bool      // Node has an edge to itself
+	beside *flowNode // Node a dummy of a back edge lies beside in its layer
+
+	layer    int         // Layer along the direction of the chart
+	order    float64     // Position within the layer while ordering
+	start    int         // First cell across the layers
+	size     int         // Cells across the layers
+	up, down []*flowNode // Neighbors in the layers before and after
+}
+
+// A flowEdge is an edge between two nodes of a flowchart.
+type flowEdge struct {
+	from, to *flowNode
+	label    string
+	arrow    bool
+}
+
+// A flowRoute is the way of an edge through the layers, from the node in the
+// first layer through dummy nodes to the node in the last.
+type flowRoute struct {
+	edge     *flowEdge
+	nodes    []*flowNode
+	reversed bool // Edge points against the direction of the chart
+	back     bool // Edge closes a cycle and runs in a lane beside the nodes
+}
+
+// next returns the node of the route in the layer after its first node.
+func (r flowRoute) next() *flowNode {
+	if r.back {
+		return r.nodes[2]
+	}
+	return r.nodes[1]
+}
+
+// A flowchart is a graph parsed from the mermaid flowchart syntax.
+type flowchart struct {
+	horizontal bool // Layers run from left to right rather than top down
+	reverse    bool // Layers run bottom up or from right to left
+	nodes      []*flowNode
+	ids        map[string]*flowNode
+	edges      []*flowEdge
+}
+
+// parseFlowchart parses a flowchart from a subset of the mermaid syntax: a
+// graph or flowchart line with the direction, then statements of nodes and
+// the links between them, such as A[Box] -->|label| B(Round) & C{Decision}.
+// The graph line may be left out for top-down charts.
+func parseFlowchart(content string) (*flowchart, error) {
+	g := &flowchart{ids: make(map[string]*flowNode)}
+	first := true
+	for _, line := range strings.Split(content, "\n") {
+		for _, statement := range strings.Split(line, ";") {
+			statement = strings.TrimSpace(statement)
+			if statement == "" || strings.HasPrefix(statement, "%%") {
+				continue
+			}
+			if first {
+				first = false
+				if match := flowHeader.FindStringSubmatch(statement); match != nil {
+					switch strings.ToUpper(match[1]) {
+					case "", "TD", "TB":
+					case "BT":
+						g.reverse = true
+					case "LR":
+						g.horizontal = true
+					case "RL":
+						g.horizontal, g.reverse = true, true
+					default:
+						return nil, fmt.Errorf("unknown direction: %s", match[1])
+					}
+					continue
+				}
+			}
+			if flowIgnored.MatchString(statement) {
+				continue
+			}
+			if err := g.parseStatement(statement); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if len(g.nodes) == 0 {
+		return nil, errors.New("no nodes")
+	}
+	return g, nil
+}
+
+// node returns the node with an ID, adding it if there is none.
+func (g *flowchart) node(id string) *flowNode {
+	if node, ok := g.ids[id]; ok {
+		return node
+	}
+	node := &flowNode{label: id}
+	g.ids[id] = node
+	g.nodes = append(g.nodes, node)
+	return node
+}
+
+// parseStatement parses a statement of nodes joined by links.
+func (g *flowchart) parseStatement(statement string) error {
+	from, rest, err := g.parseNodes(statement)
+	if err != nil {
+		return err
+	}
+	for rest != "" {
+		label, arrow, after, ok := parseFlowLink(rest)
+		if !ok {
+			return fmt.Errorf("invalid statement: %s", statement)
+		}
+		to, after, err := g.parseNodes(after)
+		if err != nil {
+			return err
+		}
+		for _, a := range from {
+			for _, b := range to {
+				g.edges = append(g.edges, &flowEdge{from: a, to: b, label: label, arrow: arrow})
+			}
+		}
+		from, rest = to, after
+	}
+	return nil
+}
+
+// parseNodes parses nodes joined by & and returns them with the rest of the
+// statement.
+func (g *flowchart) parseNodes(s string) ([]*flowNode, string, error) {
+	var nodes []*flowNode
+	for {
+		s = strings.TrimSpace(s)
+		id := flowNodeID.FindString(s)
+		if id == "" {
+			return nil, "", fmt.Errorf("missing node: %s", s)
+		}
+		node := g.node(id)
+		s = s[len(id):]
+
+		for _, shape := range flowShapes {
+			if !strings.HasPrefix(s, shape.open) {
+				continue
+			}
+			end := strings.Index(s[len(shape.open):], shape.close)
+			if end < 0 {
+				return nil, "", fmt.Errorf("unclosed %s in node %s", shape.open, id)
+			}
+			text := strings.TrimSpace(s[len(shape.open) : len(shape.open)+end])
+			node.label = strings.Trim(text, `"`)
+			node.shape = shape.shape
+			s = s[len(shape.open)+end+len(shape.close):]
+			break
+		}
+		nodes = append(nodes, node)
+
+		s = strings.TrimSpace(s)
+		if !strings.HasPrefix(s, "&") {
+			return nodes, s, nil
+		}
+		s = s[1:]
+	}
+}
+
+// parseFlowLink parses the link at the start of s and returns its label,
+// whether it ends in an arrow and the rest of s.
+func parseFlowLink(s string) (string, bool, string, bool) {
+	if link := flowLink.FindString(s); link != "" {
+		rest, label := s[len(link):], ""
+		if match := flowLinkLabel.FindStringSubmatch(rest); match != nil {
+			label, rest = strings.TrimSpace(match[1]), rest[len(match[0]):]
+		}
+		return label, strings.HasSuffix(link, ">"), rest, true
+	}
+	if match := flowLabeledLink.FindStringSubmatch(s); match != nil {
+		return match[1], strings.HasSuffix(match[2], ">"), s[len(match[0]):], true
+	}
+	return "", false, "", false
+}
+
+// layout assigns the nodes to layers along the direction of the chart, with
+// every edge pointing to a later layer except those closing a cycle, which
+// are reversed. Edges across several layers run through a dummy node in each
+// layer between. Reversed edges run through dummy nodes in the layers of their
+// ends too, right beside the nodes, so that they get a lane of their own. The
+// nodes in every layer are ordered to keep them close to
+// their neighbors, and placed across the layers with the given sizes and
+// spacing.
+func (g *flowchart) layout(size func(*flowNode) int, spacing int) ([][]*flowNode, []flowRoute) {
+	for _, node := range g.nodes {
+		node.layer, node.loop, node.up, node.down = 0, false, nil, nil
+	}
+
+	// Find the edges closing cycles in a depth-first search
+	ends := func(i int) (*flowNode, *flowNode) {
+		if g.reverse {
+			return g.edges[i].to, g.edges[i].from
+		}
+		return g.edges[i].from, g.edges[i].to
+	}
+	outgoing := make(map[*flowNode][]int)
+	for i, edge := range g.edges {
+		if edge.from == edge.to {
+			edge.from.loop = true
+			continue
+		}
+		from, _ := ends(i)
+		outgoing[from] = append(outgoing[from], i)
+	}
+	backward := make([]bool, len(g.edges))
+	state := make(map[*flowNode]int) // 1 while visiting, 2 when done
+	var visit func(node *flowNode)
+	visit = func(node *flowNode) {
+		state[node] = 1
+		for _, i := range outgoing[node] {
+			_, to := ends(i)
+			switch state[to] {
+			case 0:
+				visit(to)
+			case 1:
+				backward[i] = true
+			}
+		}
+		state[node] = 2
+	}
+	for _, node := range g.nodes {
+		if state[node] == 0 {
+			visit(node)
+		}
+	}
+	forward := func(i int) (*flowNode, *flowNode) {
+		from, to := ends(i)
+		if backward[i] {
+			return to, from
+		}
+		return from, to
+	}
+
+	// Put every node one layer after the last of its predecessors
+	successors := make(map[*flowNode][]*flowNode)
+	incoming := make(map[*flowNode]int)
+	for i, edge := range g.edges {
+		if edge.from != edge.to {
+			from, to := forward(i)
+			successors[from] = append(successors[from], to)
+			incoming[to]++
+		}
+	}
+	var queue []*flowNode
+	for _, node := range g.nodes {
+		if incoming[node] == 0 {
+			queue = append(queue, node)
+		}
+	}
+	sources := slices.Clone(queue)
+	last := 0
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		last = max(last, node.layer)
+		for _, next := range successors[node] {
+			next.layer = max(next.layer, node.layer+1)
+			if incoming[next]--; incoming[next] == 0 {
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	// Move sources down next to the first of their successors
+	for _, node := range sources {
+		if len(successors[node]) > 0 {
+			node.layer = math.MaxInt
+			for _, next := range successors[node] {
+				node.layer = min(node.layer, next.layer-1)
+			}
+		}
+	}
+
+	layers := make([][]*flowNode, last+1)
+	for _, node := range g.nodes {
+		layers[node.layer] = append(layers[node.layer], node)
+	}
+	var routes []flowRoute
+	for i, edge := range g.edges {
+		if edge.from == edge.to {
+			continue
+		}
+		from, to := forward(i)
+		route := flowRoute{edge: edge, nodes: []*flowNode{from}, reversed: backward[i] != g.reverse, back: backward[i]}
+		first, last := from.layer+1, to.layer-1
+		if route.back {
+			first, last = from.layer, to.layer
+		}
+		for layer := first; layer <= last; layer++ {
+			dummy := &flowNode{dummy: true, layer: layer}
+			switch layer {
+			case from.layer:
+				dummy.beside = from
+			case to.layer:
+				dummy.beside = to
+			}
+			layers[layer] = append(layers[layer], dummy)
+			route.nodes = append(route.nodes, dummy)
+		}
+		route.nodes = append(route.nodes, to)
+		for j := 1; j < len(route.nodes); j++ {
+			if a, b := route.nodes[j-1], route.nodes[j]; a.layer != b.layer {
+				a.down, b.up = append(a.down, b), append(b.up, a)
+			}
+		}
+		routes = append(routes, route)
+	}
+
+	// Order the layers by the mean position of the neighbors of their nodes,
+	// sweeping down and up a few times. Dummy nodes beside a node follow it.
+	up := func(node *flowNode) []*flowNode { return node.up }
+	down := func(node *flowNode) []*flowNode { return node.down }
+	for _, layer := range layers {
+		for i, node := range layer {
+			node.order = float64(i)
+		}
+	}
+	sortLayer := func(layer []*flowNode, neighbors func(*flowNode) []*flowNode) {
+		for _, node := range layer {
+			if nodes := neighbors(node); len(nodes) > 0 {
+				sum := 0.0
+				for _, other := range nodes {
+					sum += other.order
+				}
+				node.order = sum / float64(len(nodes))
+			}
+		}
+		slices.SortStableFunc(layer, func(a, b *flowNode) int { return cmp.Compare(a.order, b.order) })
+		sorted := make([]*flowNode, 0, len(layer))
+		for _, node := range layer {
+			if node.beside != nil {
+				continue
+			}
+			sorted = append(sorted, node)
+			for _, other := range layer {
+				if other.beside == node {
+					sorted = append(sorted, other)
+				}
+			}
+		}
+		copy(layer, sorted)
+		for i, node := range layer {
+			node.order = float64(i)
+		}
+	}
+	for range 4 {
+		for k := 1; k < len(layers); k++ {
+			sortLayer(layers[k], up)
+		}
+		for k := len(layers) - 2; k >= 0; k-- {
+			sortLayer(layers[k], down)
+		}
+	}
+
+	// Place the nodes across the layers, centering them on their neighbors
+	// as far as the nodes before them allow
+	for _, layer := range layers {
+		next := 0
+		for _, node := range layer {
+			node.size = size(node)
+			node.start = next
+			next += node.size + spacing
+		}
+	}
+	place := func(layer []*flowNode, neighbors func(*flowNode) []*flowNode) {
+		next := math.MinInt
+		for _, node := range layer {
+			start := node.start
+			if nodes := neighbors(node); len(nodes) > 0 {
+				sum := 0
+				for _, other := range nodes {
+					sum += other.start + other.size/2
+				}
+				start = sum/len(nodes) - node.size/2
+			}
+			node.start = max(start, next)
+			next = node.start + node.size + spacing
+		}
+	}
+	for k := 1; k < len(layers); k++ {
+		place(layers[k], up)
+	}
+	for k := len(layers) - 2; k >= 0; k-- {
+		place(layers[k], down)
+	}
+	for k := 1; k < len(layers); k++ {
+		place(layers[k], up)
+	}
+	first := math.MaxInt
+	for _, layer := range layers {
+		first = min(first, layer[0].start)
+	}
+	for _, layer := range layers {
+		for _, node := range layer {
+			node.start -= first
+		}
+	}
+	return layers, routes
+}
+
+// draw lays out the chart and draws it, or returns ErrUnprocessed if it is
+// wider than width.
+func (g *flowchart) draw(width int) ([]string, error) {
+	label := func(node *flowNode) string {
+		if node.loop {
+			return node.label + " ↺"
+		}
+		return node.label
+	}
+	// Sizes of nodes along and across the layers
+	along := func(node *flowNode) int {
+		switch {
+		case node.dummy:
+			return 0
+		case g.horizontal:
+			return textWidth(label(node)) + 4
+		default:
+			return 3
+		}
+	}
+	across := func(node *flowNode) int {
+		switch {
+		case node.dummy:
+			return 1
+		case g.horizontal:
+			return 3
+		default:
+			return textWidth(label(node)) + 4
+		}
+	}
+	spacing := 2
+	if g.horizontal {
+		spacing = 1
+	}
+	layers, routes := g.layout(across, spacing)
+
+	// Layers are as long as their longest node, and the gaps between them
+	// make room for the labels of the edges ending in the next layer. Back
+	// edges moving to another lane top down cross over in a row of their
+	// own, between the row the forward edges jog in and the arrows.
+	sizes := make([]int, len(layers))
+	labeled := make([]bool, len(layers))
+	crossed := make([]bool, len(layers))
+	for k, layer := range layers {
+		sizes[k] = 1
+		for _, node := range layer {
+			sizes[k] = max(sizes[k], along(node))
+		}
+	}
+	gaps := make([]int, len(layers))
+	for _, route := range routes {
+		if route.back {
+			lanes := route.nodes[1 : len(route.nodes)-1]
+			for j := 1; j < len(lanes); j++ {
+				if lanes[j].start != lanes[j-1].start {
+					crossed[lanes[j-1].layer] = true
+				}
+			}
+		}
+		if route.edge.label != "" {
+			k := route.nodes[0].layer
+			labeled[k] = true
+			gaps[k] = max(gaps[k], textWidth(route.edge.label)+7)
+		}
+	}
+	for k := range layers {
+		if g.horizontal {
+			gaps[k] = max(gaps[k], 4)
+		} else {
+			gaps[k] = 3 + btoi(labeled[k]) + btoi(crossed[k])
+		}
+	}
+	positions := make([]int, len(layers))
+	for k := 1; k < len(layers); k++ {
+		positions[k] = positions[k-1] + sizes[k-1] + gaps[k-1]
+	}
+	length := positions[len(layers)-1] + sizes[len(layers)-1]
+	breadth := 0
+	for _, layer := range layers {
+		last := layer[len(layer)-1]
+		breadth = max(breadth, last.start+last.size)
+	}
+	if !g.horizontal {
+		// Labels go to the right of the edges
+		for _, route := range routes {
+			if next := route.next(); route.edge.label != "" {
+				breadth = max(breadth, next.start+next.size/2+2+textWidth(route.edge.label))
+			}
+		}
+	}
+
+	point := func(along, across int) (int, int) {
+		if g.horizontal {
+			return along, across
+		}
+		return across, along
+	}
+	var c *canvas
+	if g.horizontal {
+		c = newCanvas(length, breadth)
+	} else {
+		c = newCanvas(breadth, length)
+	}
+	if c.width > width {
+		return nil, ErrUnprocessed
+	}
+
+	for _, layer := range layers {
+		for _, node := range layer {
+			if node.dummy {
+				continue
+			}
+			x, y := point(positions[node.layer], node.start)
+			w, h := along(node), 3
+			if !g.horizontal {
+				w = node.size
+			}
+			switch node.shape {
+			case flowRound:
+				c.box(x, y, w, h, "╭╮╰╯", label(node))
+			case flowDecision:
+				c.box(x, y, w, h, "╱╲╲╱", label(node))
+				c.set(x, y+1, '<')
+				c.set(x+w-1, y+1, '>')
+			default:
+				c.box(x, y, w, h, "┌┐└┘", label(node))
+			}
+		}
+	}
+
+	arrows := [3]rune{'▼', '▲', '◀'}                           // Down, up and into the side of a box
+	teeChars := [3][2]rune{{'─', '┬'}, {'─', '┴'}, {'│', '├'}} // Box borders where edges leave and enter
+	if g.horizontal {
+		arrows = [3]rune{'▶', '◀', '▲'}
+		teeChars = [3][2]rune{{'│', '├'}, {'│', '┤'}, {'─', '┬'}}
+	}
+	// Back edges meet their nodes in the middle of the side
+	middle := func(node *flowNode) int {
+		if g.horizontal {
+			return positions[node.layer] + along(node)/2
+		}
+		return positions[node.layer] + 1
+	}
+	type flowTee struct {
+		border, end [2]int
+		chars       [2]rune
+	}
+	var tees []flowTee
+	for _, route := range routes {
+		var points [][2]int
+		add := func(along, across int) {
+			x, y := point(along, across)
+			points = append(points, [2]int{x, y})
+		}
+		var tailBorder, headBorder [2]int // Borders next to the first and last point
+		tailTee, headTee := teeChars[0], teeChars[1]
+		arrow := arrows[0]
+		if route.reversed {
+			arrow = arrows[1]
+		}
+
+		first, last := route.nodes[0], route.nodes[len(route.nodes)-1]
+		if route.back {
+			// Back edges leave their first node at the side to the lane of
+			// dummy nodes beside it, and enter the last one at the side
+			lanes := route.nodes[1 : len(route.nodes)-1]
+			lane := lanes[0].start
+			add(middle(first), first.start+first.size)
+			add(middle(first), lane)
+			for _, next := range lanes[1:] {
+				jog := positions[next.layer] - 2
+				add(jog, lane)
+				lane = next.start
+				add(jog, lane)
+			}
+			add(middle(last), lane)
+			add(middle(last), last.start+last.size)
+			tailBorder[0], tailBorder[1] = point(middle(first), first.start+first.size-1)
+			headBorder[0], headBorder[1] = point(middle(last), last.start+last.size-1)
+			tailTee, headTee, arrow = teeChars[2], teeChars[2], arrows[2]
+		} else {
+			node := first
+			center := node.start + node.size/2
+			exit := positions[node.layer] + along(node)
+			add(exit, center)
+			for _, next := range route.nodes[1:] {
+				jog := positions[node.layer] + sizes[node.layer] + 1
+				add(jog, center)
+				center = next.start + next.size/2
+				add(jog, center)
+				node = next
+			}
+			end := positions[node.layer] - 1
+			add(end, center)
+			tailBorder[0], tailBorder[1] = point(exit-1, first.start+first.size/2)
+			headBorder[0], headBorder[1] = point(end+1, center)
+		}
+		c.path(points)
+
+		// Labels go next to the edge where it enters the layer after its
+		// first node, where it does not yet share the way with other edges
+		if text := route.edge.label; text != "" {
+			next := route.next()
+			jog := positions[first.layer] + sizes[first.layer] + 1
+			if g.horizontal {
+				c.text(jog+2, next.start+next.size/2, " "+text+" ", true)
+			} else {
+				c.text(next.start+next.size/2+2, jog+1, text, false)
+			}
+		}
+
+		// An arrow at the end the edge points to, tees where the other
+		// end meets a box
+		head, tail := points[len(points)-1], points[0]
+		if route.reversed {
+			head, tail = tail, head
+			headBorder, tailBorder = tailBorder, headBorder
+			tailTee, headTee = headTee, tailTee
+		}
+		if route.edge.arrow {
+			c.set(head[0], head[1], arrow)
+		} else {
+			tees = append(tees, flowTee{headBorder, head, headTee})
+		}
+		tees = append(tees, flowTee{tailBorder, tail, tailTee})
+	}
+
+	// Edges meet the borders of boxes in tees, except next to arrows
+	for _, tee := range tees {
+		if slices.Contains(arrows[:], c.at(tee.end[0], tee.end[1])) {
+			continue
+		}
+		if c.at(tee.border[0], tee.border[1]) == tee.chars[0] {
+			c.set(tee.border[0], tee.border[1], tee.chars[1])
+		}
+	}
+	return c.render(), nil
+}
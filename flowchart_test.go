@@ -0,0 +1,141 @@
+package unidoc
+
+import "testing"
+
+func TestFlowchart(t *testing.T) {
+	tests := []struct {
+		name, language, content string
+		width                   int
+		want                    string
+	}{
+		{"shapes and labels", "mermaid", "graph TD\n  A[Start] --> B{Ok?}\n  B -->|yes| C(Done)\n  B -->|no| D[Retry]", 40, `
+┌───────┐
+│ Start │
+└───┬───┘
+    │
+    │
+    ▼
+ ╱─────╲
+ < Ok? >
+ ╲──┬──╱
+    │
+    ├─────────╮
+    │ yes     │ no
+    ▼         ▼
+╭──────╮  ┌───────┐
+│ Done │  │ Retry │
+╰──────╯  └───────┘`},
+		{"left to right", "mermaid", "graph LR\n  A --> B --> C", 40, `
+┌───┐    ┌───┐    ┌───┐
+│ A ├───▶│ B ├───▶│ C │
+└───┘    └───┘    └───┘`},
+		{"bottom to top", "mermaid", "graph BT\n  A --> B", 30, `
+┌───┐
+│ B │
+└───┘
+  ▲
+  │
+  │
+┌─┴─┐
+│ A │
+└───┘`},
+		{"right to left", "mermaid", "graph RL\n  A --> B", 30, `
+┌───┐    ┌───┐
+│ B │◀───┤ A │
+└───┘    └───┘`},
+		{"back edge", "mermaid", "graph TD\n  A --> B --> C\n  C --> A", 30, `
+┌───┐
+│ A │◀─╮
+└─┬─┘  │
+  │    │
+  │    │
+  ▼    │
+┌───┐  │
+│ B │  │
+└─┬─┘  │
+  │    │
+  │    │
+  ▼    │
+┌───┐  │
+│ C ├──╯
+└───┘`},
+		{"back edge left to right", "mermaid", "graph LR\n  A --> B --> C\n  C --> A", 40, `
+┌───┐    ┌───┐    ┌───┐
+│ A ├───▶│ B ├───▶│ C │
+└───┘    └───┘    └─┬─┘
+  ▲                 │
+  ╰─────────────────╯`},
+		{"back edge changing lanes", "mermaid", "graph TD\n  A --> B --> C --> D\n  A --> X --> D\n  D --> B", 30, `
+┌───┐
+│ A │
+└─┬─┘
+  │
+  ├─────────╮
+  ▼         ▼
+┌───┐     ┌───┐
+│ B │◀─╮  │ X │
+└─┬─┘  │  └─┬─┘
+  │    │    │
+  │    │    │
+  │    ╰────┼──╮
+  ▼         │  │
+┌───┐       │  │
+│ C │       │  │
+└─┬─┘       │  │
+  │         │  │
+  ╰────┬────╯  │
+       ▼       │
+     ┌───┐     │
+     │ D ├─────╯
+     └───┘`},
+		{"back edge changing lanes left to right", "mermaid", "graph LR\n  A --> B & C\n  B --> D\n  C --> D\n  D --> A", 40, `
+┌───┐    ┌───┐
+│ A ├─┬─▶│ B ├─╮
+└───┘ │  └───┘ │  ┌───┐
+  ▲   │        ├─▶│ D │
+  │   │  ┌───┐ │  └─┬─┘
+  │   ╰─▶│ C ├─╯    │
+  ╰────╮ └───┘      │
+       │            │
+       ╰────────────╯`},
+		{"self loop", "mermaid", "graph TD\n  A --> A", 30, `
+┌─────┐
+│ A ↺ │
+└─────┘`},
+		{"graph block", "graph", "A --> B", 30, `
+┌───┐
+│ A │
+└─┬─┘
+  │
+  │
+  ▼
+┌───┐
+│ B │
+└───┘`},
+		{"too wide left to right", "mermaid", "graph LR\n  A[aaaaaaaaaa] --> B[bbbbbbbbbb] --> C[cccccccccc]", 30, `
+┌────────────┐
+│ aaaaaaaaaa │
+└──────┬─────┘
+       │
+       │
+       ▼
+┌────────────┐
+│ bbbbbbbbbb │
+└──────┬─────┘
+       │
+       │
+       ▼
+┌────────────┐
+│ cccccccccc │
+└────────────┘`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := processTest(t, FencedBlock{Language: tt.language, Content: tt.content}, tt.width)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkOutput(t, got, tt.want)
+		})
+	}
+}
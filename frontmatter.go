@@ -0,0 +1,280 @@
+package unidoc
+
+import (
+	"bytes"
+	"strings"
+
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+	"gopkg.in/yaml.v3"
+)
+
+// Metadata holds the document information from YAML front matter.
+type Metadata struct {
+	Title    string
+	Subtitle string
+	Authors  []string
+	Date     string
+	Tags     []string
+
+	Options map[string]any // Rendering options from the unidoc key
+	Extra   map[string]any // Remaining front matter keys
+}
+
+// stringList decodes a YAML scalar or a sequence of scalars.
+type stringList []string
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface for stringList.
+func (l *stringList) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*l = stringList{value.Value}
+		return nil
+	}
+	return value.Decode((*[]string)(l))
+}
+
+// parseMetadata decodes YAML front matter. Tags given as a single string are
+// split at commas, and both "author" and "authors" are accepted.
+func parseMetadata(src []byte) (*Metadata, error) {
+	var raw struct {
+		Title    string         `yaml:"title"`
+		Subtitle string         `yaml:"subtitle"`
+		Author   stringList     `yaml:"author"`
+		Authors  stringList     `yaml:"authors"`
+		Date     string         `yaml:"date"`
+		Tags     stringList     `yaml:"tags"`
+		Options  map[string]any `yaml:"unidoc"`
+		Extra    map[string]any `yaml:",inline"`
+	}
+	if err := yaml.Unmarshal(src, &raw); err != nil {
+		return nil, err
+	}
+
+	meta := &Metadata{
+		Title:    strings.TrimSpace(raw.Title),
+		Subtitle: strings.TrimSpace(raw.Subtitle),
+		Authors:  append(raw.Author, raw.Authors...),
+		Date:     strings.TrimSpace(raw.Date),
+		Options:  raw.Options,
+		Extra:    raw.Extra,
+	}
+	if len(raw.Tags) == 1 {
+		raw.Tags = strings.Split(raw.Tags[0], ",")
+	}
+	for _, tag := range raw.Tags {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			meta.Tags = append(meta.Tags, tag)
+		}
+	}
+	return meta, nil
+}
+
+// A FrontMatter represents a YAML metadata block at the start of a document:
+//
+//	---
+//	title: Release Notes
+//	author: Jane Doe
+//	---
+type FrontMatter struct {
+	gast.BaseBlock
+	Metadata *Metadata
+	closing  int // Source offset of the closing fence
+}
+
+// KindFrontMatter is a NodeKind of the FrontMatter node.
+var KindFrontMatter = gast.NewNodeKind("FrontMatter")
+
+// Kind implements Node.Kind.
+func (n *FrontMatter) Kind() gast.NodeKind {
+	return KindFrontMatter
+}
+
+// IsRaw implements Node.IsRaw.
+func (n *FrontMatter) IsRaw() bool {
+	return true
+}
+
+// Dump implements Node.Dump.
+func (n *FrontMatter) Dump(source []byte, level int) {
+	gast.DumpHelper(n, source, level, map[string]string{
+		"Title": n.Metadata.Title,
+	}, nil)
+}
+
+type frontMatterParser struct{}
+
+// newFrontMatterParser returns a new BlockParser that parses YAML front
+// matter delimited by --- lines on the first line of a document.
+func newFrontMatterParser() parser.BlockParser {
+	return &frontMatterParser{}
+}
+
+// isFrontMatterFence reports whether line opens or closes front matter.
+func isFrontMatterFence(line []byte, closing bool) bool {
+	line = util.TrimRightSpace(line)
+	return bytes.Equal(line, []byte("---")) || closing && bytes.Equal(line, []byte("..."))
+}
+
+func (b *frontMatterParser) Trigger() []byte {
+	return []byte{'-'}
+}
+
+// Open only accepts a block that is closed and decodes as a YAML mapping, so
+// that documents starting with a thematic break keep rendering as before.
+func (b *frontMatterParser) Open(parent gast.Node, reader text.Reader, _ parser.Context) (gast.Node, parser.State) {
+	line, segment := reader.PeekLine()
+	if num, _ := reader.Position(); num != 0 || parent.Kind() != gast.KindDocument || !isFrontMatterFence(line, false) {
+		return nil, parser.NoChildren
+	}
+
+	// Look ahead for the closing fence
+	source := reader.Source()
+	start, closing := segment.Stop, -1
+	for pos := start; pos < len(source); {
+		end := bytes.IndexByte(source[pos:], '\n') + 1
+		if end == 0 {
+			end = len(source) - pos
+		}
+		if isFrontMatterFence(source[pos:pos+end], true) {
+			closing = pos
+			break
+		}
+		pos += end
+	}
+	if closing < 0 {
+		return nil, parser.NoChildren
+	}
+
+	meta, err := parseMetadata(source[start:closing])
+	if err != nil {
+		return nil, parser.NoChildren
+	}
+
+	reader.Advance(segment.Len() - trailingNewline(line))
+	return &FrontMatter{Metadata: meta, closing: closing}, parser.NoChildren
+}
+
+func (b *frontMatterParser) Continue(node gast.Node, reader text.Reader, _ parser.Context) parser.State {
+	n := node.(*FrontMatter)
+	line, segment := reader.PeekLine()
+	reader.Advance(segment.Len() - trailingNewline(line))
+	if segment.Start >= n.closing {
+		return parser.Close
+	}
+
+	n.Lines().Append(segment)
+	return parser.Continue | parser.NoChildren
+}
+
+func (b *frontMatterParser) Close(_ gast.Node, _ text.Reader, _ parser.Context) {
+	// nothing to do
+}
+
+func (b *frontMatterParser) CanInterruptParagraph() bool {
+	return false
+}
+
+func (b *frontMatterParser) CanAcceptIndentedLine() bool {
+	return false
+}
+
+// joinNames joins names into an English list: "A, B and C".
+func joinNames(names []string) string {
+	if len(names) <= 1 {
+		return strings.Join(names, "")
+	}
+	return strings.Join(names[:len(names)-1], ", ") + " and " + names[len(names)-1]
+}
+
+// titleBlock returns the heading lines (title and subtitle) and the
+// information lines (authors, date and tags) of a title block, wrapped to
+// the given width.
+func (r *UnicodeRenderer) titleBlock(meta *Metadata, width int) (heading, info []string) {
+	if meta.Title != "" {
+		for _, line := range wrapLine(meta.Title, width) {
+			heading = append(heading, toBoldSansSerifText(r.toSmartDashes(line)))
+		}
+	}
+	if meta.Subtitle != "" {
+		for _, line := range wrapLine(meta.Subtitle, width) {
+			heading = append(heading, r.italicText(r.toSmartDashes(line)))
+		}
+	}
+
+	if len(meta.Authors) > 0 {
+		info = append(info, wrapLine("by "+joinNames(meta.Authors), width)...)
+	}
+	if meta.Date != "" {
+		info = append(info, meta.Date)
+	}
+	if len(meta.Tags) > 0 {
+		badges := make([]string, len(meta.Tags))
+		for i, tag := range meta.Tags {
+			badges[i] = "⟦" + tag + "⟧"
+		}
+		info = append(info, wrapLine(strings.Join(badges, " "), width)...)
+	}
+	return heading, info
+}
+
+// FrontMatter renderer
+func (r *UnicodeRenderer) renderFrontMatter(
+	w util.BufWriter,
+	_ []byte,
+	node gast.Node,
+	entering bool,
+) (gast.WalkStatus, error) {
+	if !entering {
+		return gast.WalkContinue, nil
+	}
+
+	n := node.(*FrontMatter)
+	center := func(lines []string, width int) []string {
+		for i, line := range lines {
+			lines[i] = strings.TrimRight(centerText(line, width), " ")
+		}
+		return lines
+	}
+
+	var b strings.Builder
+	switch r.config.TitleStyle {
+	case TitleStylePlain:
+		heading, info := r.titleBlock(n.Metadata, r.width)
+		for _, line := range append(heading, info...) {
+			b.WriteString(line + "\n")
+		}
+
+	case TitleStyleBanner:
+		heading, info := r.titleBlock(n.Metadata, r.width)
+		rule := strings.Repeat("━", r.width) + "\n"
+		if len(heading) > 0 {
+			b.WriteString(rule)
+			for _, line := range center(heading, r.width) {
+				b.WriteString(line + "\n")
+			}
+			b.WriteString(rule)
+		}
+		for _, line := range center(info, r.width) {
+			b.WriteString(line + "\n")
+		}
+
+	case TitleStyleBox:
+		heading, info := r.titleBlock(n.Metadata, r.width-6)
+		if len(heading) > 0 {
+			b.WriteString(drawBox(center(heading, r.width-4), r.width, "", BoxStyleDouble))
+		}
+		for _, line := range center(info, r.width) {
+			b.WriteString(line + "\n")
+		}
+	}
+
+	if b.Len() > 0 {
+		b.WriteString("\n")
+	}
+	if _, err := w.WriteString(b.String()); err != nil {
+		return gast.WalkStop, err
+	}
+	return gast.WalkSkipChildren, nil
+}
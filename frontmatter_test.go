@@ -0,0 +1,125 @@
+package unidoc
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+const titleDocument = `---
+title: Release Notes
+subtitle: Version 2
+author: Jane Doe
+date: 2024-05-01
+---
+
+Body`
+
+func TestTitleBlock(t *testing.T) {
+	tests := []struct {
+		style TitleStyle
+		want  string
+	}{
+		{TitleStyleBox, `
+╔════════════════════════════╗
+║       𝗥𝗲𝗹𝗲𝗮𝘀𝗲 𝗡𝗼𝘁𝗲𝘀        ║
+║         𝘝𝘦𝘳𝘴𝘪𝘰𝘯 2          ║
+╚════════════════════════════╝
+         by Jane Doe
+          2024-05-01
+
+Body`},
+		{TitleStyleBanner, `
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+        𝗥𝗲𝗹𝗲𝗮𝘀𝗲 𝗡𝗼𝘁𝗲𝘀
+          𝘝𝘦𝘳𝘴𝘪𝘰𝘯 2
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+         by Jane Doe
+          2024-05-01
+
+Body`},
+		{TitleStylePlain, `
+𝗥𝗲𝗹𝗲𝗮𝘀𝗲 𝗡𝗼𝘁𝗲𝘀
+𝘝𝘦𝘳𝘴𝘪𝘰𝘯 2
+by Jane Doe
+2024-05-01
+
+Body`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.style.String(), func(t *testing.T) {
+			got := convertTest(t, titleDocument, func(c *Config) { c.TitleStyle = tt.style })
+			checkOutput(t, got, tt.want)
+		})
+	}
+}
+
+func TestTitleBlockWrap(t *testing.T) {
+	got := convertTest(t, "---\ntitle: A very long title that does not fit on one line\n---\n")
+	checkOutput(t, got, `
+╔════════════════════════════╗
+║   𝗔 𝘃𝗲𝗿𝘆 𝗹𝗼𝗻𝗴 𝘁𝗶𝘁𝗹𝗲 𝘁𝗵𝗮𝘁   ║
+║  𝗱𝗼𝗲𝘀 𝗻𝗼𝘁 𝗳𝗶𝘁 𝗼𝗻 𝗼𝗻𝗲 𝗹𝗶𝗻𝗲  ║
+╚════════════════════════════╝`)
+}
+
+func TestParseMetadata(t *testing.T) {
+	meta, err := parseMetadata([]byte("title: \" T \"\nauthor: A\nauthors: [B, C]\ntags: x, y ,\nlicense: MIT\nunidoc:\n  width: 40\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.Title != "T" {
+		t.Errorf("Title = %q, want T", meta.Title)
+	}
+	if want := []string{"A", "B", "C"}; !slices.Equal(meta.Authors, want) {
+		t.Errorf("Authors = %q, want %q", meta.Authors, want)
+	}
+	if want := []string{"x", "y"}; !slices.Equal(meta.Tags, want) {
+		t.Errorf("Tags = %q, want %q", meta.Tags, want)
+	}
+	if meta.Extra["license"] != "MIT" {
+		t.Errorf("Extra = %v, want license MIT", meta.Extra)
+	}
+	if meta.Options["width"] != 40 {
+		t.Errorf("Options = %v, want width 40", meta.Options)
+	}
+
+	if _, err := parseMetadata([]byte("title: [unclosed")); err == nil {
+		t.Error("parseMetadata of invalid YAML succeeded")
+	}
+}
+
+func TestFrontMatterMetadata(t *testing.T) {
+	result, err := ConvertDocument([]byte("---\nauthor: [A, B]\n---\nBody"), DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Metadata == nil || !slices.Equal(result.Metadata.Authors, []string{"A", "B"}) {
+		t.Errorf("Metadata = %+v, want authors A and B", result.Metadata)
+	}
+	if !strings.HasSuffix(result.Text, "by A and B\n\nBody") {
+		t.Errorf("Text = %q, want the authors above the body", result.Text)
+	}
+}
+
+func TestFrontMatterInvalid(t *testing.T) {
+	// Front matter that is not YAML, not closed or not at the start of the
+	// document is rendered as Markdown
+	for _, input := range []string{
+		"---\ntitle: [unclosed\n---\nBody",
+		"---\ntitle: Only\nBody",
+		"Intro\n\n---\ntitle: x\n---",
+	} {
+		result, err := ConvertDocument([]byte(input), DefaultConfig())
+		if err != nil {
+			t.Errorf("ConvertDocument(%q): %v", input, err)
+			continue
+		}
+		if result.Metadata != nil {
+			t.Errorf("ConvertDocument(%q) has metadata %+v", input, result.Metadata)
+		}
+		if !strings.Contains(result.Text, "title") && !strings.Contains(result.Text, "𝘁𝗶𝘁𝗹𝗲") {
+			t.Errorf("ConvertDocument(%q) = %q, want the text kept", input, result.Text)
+		}
+	}
+}
@@ -0,0 +1,319 @@
+package unidoc
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ganttDuration matches the duration of a task in hours, days or weeks, such
+// as "12h", "3d" or "2w".
+var ganttDuration = regexp.MustCompile(`^(\d+(?:\.\d+)?)([hdw])$`)
+
+// ganttIgnored matches mermaid gantt settings that do not change the drawing.
+var ganttIgnored = regexp.MustCompile(`^(?:gantt|dateFormat|axisFormat|tickInterval|excludes|includes|todayMarker|weekday|inclusiveEndDates|displayMode)\b`)
+
+// ganttUnits holds the lengths of the units of task durations.
+var ganttUnits = map[string]time.Duration{"h": time.Hour, "d": 24 * time.Hour, "w": 7 * 24 * time.Hour}
+
+// A ganttTask is a bar or a milestone of a gantt chart. The end of a task is
+// exclusive, and milestones end where they start.
+type ganttTask struct {
+	label      string
+	section    string
+	start, end time.Time
+	done       bool
+	milestone  bool
+}
+
+// A gantt holds the tasks of a gantt chart in the order they are listed.
+type gantt struct {
+	title string
+	tasks []ganttTask
+}
+
+// parseGantt parses a gantt chart in a subset of the mermaid syntax, with a
+// task on every line as "label: [tags,] [id,] [start,] end". Tags are done,
+// active, crit and milestone. The start is a date such as 2024-07-01 or
+// "after" a list of task IDs, and the end a date or a duration. Tasks without
+// a start follow the previous task, and milestones only need a start. A lone
+// date marks a point in time like a milestone in timelines, and in gantt
+// charts for the first task, which has no previous task to follow.
+func parseGantt(content string, timeline bool) (gantt, error) {
+	var g gantt
+	var section string
+	var previous time.Time
+	ends := make(map[string]time.Time)
+	for i, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "%%") || ganttIgnored.MatchString(line) {
+			continue
+		}
+		if title, ok := strings.CutPrefix(line, "title "); ok {
+			g.title = strings.TrimSpace(title)
+			continue
+		}
+		if name, ok := strings.CutPrefix(line, "section "); ok {
+			section = strings.TrimSpace(name)
+			continue
+		}
+
+		label, meta, ok := strings.Cut(line, ":")
+		if !ok {
+			return g, fmt.Errorf("line %d: invalid task: %s", i+1, line)
+		}
+		task := ganttTask{label: strings.TrimSpace(label), section: section}
+		var items []string
+		for _, item := range strings.Split(meta, ",") {
+			switch item = strings.TrimSpace(item); item {
+			case "done":
+				task.done = true
+			case "milestone":
+				task.milestone = true
+			case "active", "crit", "":
+			default:
+				items = append(items, item)
+			}
+		}
+		var id string
+		if len(items) == 3 {
+			id, items = items[0], items[1:]
+		}
+		if len(items) == 0 || len(items) > 2 {
+			return g, fmt.Errorf("line %d: invalid task: %s", i+1, line)
+		}
+
+		if len(items) == 1 && (timeline || previous.IsZero()) {
+			if _, err := time.Parse(time.DateOnly, items[0]); err == nil {
+				task.milestone = true
+			}
+		}
+
+		var err error
+		task.start = previous
+		if len(items) == 2 || task.milestone {
+			if task.start, err = ganttStart(items[0], ends); err != nil {
+				return g, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			items = items[1:]
+		}
+		if task.start.IsZero() {
+			return g, fmt.Errorf("line %d: missing start date: %s", i+1, line)
+		}
+		task.end = task.start
+		if len(items) > 0 && !task.milestone {
+			if task.end, err = ganttEnd(task.start, items[0]); err != nil {
+				return g, fmt.Errorf("line %d: %w", i+1, err)
+			}
+		}
+
+		previous = task.end
+		if id != "" {
+			ends[id] = task.end
+		}
+		g.tasks = append(g.tasks, task)
+	}
+	if len(g.tasks) == 0 {
+		return g, fmt.Errorf("no tasks")
+	}
+	return g, nil
+}
+
+// ganttStart parses the start of a task, a date or "after" the tasks with the
+// given IDs, starting when the last of them ends.
+func ganttStart(text string, ends map[string]time.Time) (time.Time, error) {
+	ids, ok := strings.CutPrefix(text, "after ")
+	if !ok {
+		start, err := time.Parse(time.DateOnly, text)
+		if err != nil {
+			return start, fmt.Errorf("invalid start: %s", text)
+		}
+		return start, nil
+	}
+
+	var start time.Time
+	for _, id := range strings.Fields(ids) {
+		end, ok := ends[id]
+		if !ok {
+			return start, fmt.Errorf("unknown task: %s", id)
+		}
+		if end.After(start) {
+			start = end
+		}
+	}
+	return start, nil
+}
+
+// ganttEnd parses the end of a task, a date or a duration from its start.
+func ganttEnd(start time.Time, text string) (time.Time, error) {
+	if match := ganttDuration.FindStringSubmatch(text); match != nil {
+		value, _ := strconv.ParseFloat(match[1], 64)
+		return start.Add(time.Duration(value * float64(ganttUnits[match[2]]))), nil
+	}
+	end, err := time.Parse(time.DateOnly, text)
+	if err != nil || end.Before(start) {
+		return end, fmt.Errorf("invalid end: %s", text)
+	}
+	return end, nil
+}
+
+// A ganttStep is the spacing of the ticks on the time axis of a gantt chart,
+// in days or in months, with the layout of their labels.
+type ganttStep struct {
+	days, months int
+	layout       string
+}
+
+// ganttSteps holds the spacings of ticks from the finest to the coarsest.
+var ganttSteps = []ganttStep{
+	{days: 1, layout: "Jan 2"},
+	{days: 2, layout: "Jan 2"},
+	{days: 7, layout: "Jan 2"},
+	{days: 14, layout: "Jan 2"},
+	{months: 1, layout: "Jan"},
+	{months: 3, layout: "Jan"},
+	{months: 12, layout: "2006"},
+	{months: 60, layout: "2006"},
+}
+
+// draw lays out a gantt chart with the task labels in a column on the left,
+// grouped by section, and the bars on a time axis filling the rest of the
+// width. Done tasks are shaded and milestones drawn as diamonds:
+//
+//	Backend        │
+//	  Design API   │██████
+//	  Launch       │      ◆
+//	               └┴──────┴
+//	                Jul 1  Jul 8
+func (g gantt) draw(width int) []string {
+	first, last := g.tasks[0].start, g.tasks[0].end
+	labelWidth, indent := 0, ""
+	for _, task := range g.tasks {
+		if task.start.Before(first) {
+			first = task.start
+		}
+		if task.end.After(last) {
+			last = task.end
+		}
+		if task.milestone && !task.start.Before(last) {
+			last = task.start.AddDate(0, 0, 1) // Room for the diamond
+		}
+		if task.section != "" {
+			labelWidth, indent = max(labelWidth, textWidth(task.section)), "  "
+		}
+	}
+	for _, task := range g.tasks {
+		labelWidth = max(labelWidth, textWidth(indent+task.label))
+	}
+	if !last.After(first) {
+		last = first.AddDate(0, 0, 1)
+	}
+	labelWidth = min(labelWidth, width/3)
+	size := max(width-labelWidth-2, 8)
+
+	span := float64(last.Sub(first))
+	column := func(t time.Time) int {
+		return int(math.Round(float64(t.Sub(first)) / span * float64(size)))
+	}
+
+	var lines []string
+	if g.title != "" {
+		lines = append(lines, toBoldSansSerifText(truncateText(g.title, width)))
+	}
+	section := ""
+	for _, task := range g.tasks {
+		if task.section != section {
+			section = task.section
+			lines = append(lines, padRight(toBoldSansSerifText(truncateText(section, labelWidth)), labelWidth)+" │")
+		}
+		start := min(column(task.start), size-1)
+		bar := strings.Repeat(" ", start)
+		switch {
+		case task.milestone:
+			bar += "◆"
+		case task.done:
+			bar += strings.Repeat("▒", max(column(task.end)-start, 1))
+		default:
+			bar += strings.Repeat("█", max(column(task.end)-start, 1))
+		}
+		label := padRight(truncateText(indent+task.label, labelWidth), labelWidth)
+		lines = append(lines, label+" │"+bar)
+	}
+
+	// Ticks are as close as their labels allow
+	step := ganttSteps[len(ganttSteps)-1]
+	for _, candidate := range ganttSteps {
+		days := float64(candidate.days) + float64(candidate.months)*30.4
+		if days*24*float64(time.Hour)/span*float64(size) >= float64(len(candidate.layout)+2) {
+			step = candidate
+			break
+		}
+	}
+	tick := first
+	if step.months > 0 {
+		month := int(first.Month()) - 1
+		tick = time.Date(first.Year(), time.Month(month-month%step.months+1), 1, 0, 0, 0, 0, time.UTC)
+	}
+	axis := []rune(strings.Repeat("─", size))
+	var labels strings.Builder
+	next := 0 // First column free for a label
+	for ; !tick.After(last); tick = tick.AddDate(0, step.months, step.days) {
+		x := column(tick)
+		if tick.Before(first) || x >= size {
+			continue
+		}
+		axis[x] = '┴'
+		label := tick.Format(step.layout)
+		switch {
+		case step.months == 0 || step.months >= 12:
+		case next == 0:
+			label = tick.Format("Jan 2006")
+		case tick.Month() == time.January:
+			label = tick.Format("2006")
+		}
+		x = min(x, size-len(label))
+		if x >= next {
+			labels.WriteString(strings.Repeat(" ", x-labels.Len()) + label)
+			next = labels.Len() + 1
+		}
+	}
+	lines = append(lines, strings.Repeat(" ", labelWidth)+" └"+string(axis))
+	lines = append(lines, strings.Repeat(" ", labelWidth+2)+labels.String())
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return lines
+}
+
+// ganttProcessor renders ```gantt and ```timeline blocks with a project plan
+// in the syntax of mermaid gantt charts as bars on a time axis:
+//
+//	```gantt {title="Q3 plan"}
+//	section Backend
+//	Design API: design, 2024-07-01, 10d
+//	Implement: after design, 3w
+//	Launch: milestone, 2024-08-15
+//
+// Timeline blocks that are not plans are read as mermaid timelines of
+// periods and their events.
+type ganttProcessor struct{}
+
+// ProcessBlock implements BlockProcessor.ProcessBlock.
+func (p ganttProcessor) ProcessBlock(block FencedBlock, width int) ([]string, error) {
+	g, err := parseGantt(block.Content, block.Language == "timeline")
+	if err != nil && block.Language == "timeline" {
+		if t, err := parseTimeline(block.Content); err == nil {
+			t.title = cmp.Or(block.Attributes["title"], t.title)
+			return t.draw(width), nil
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnprocessed, err)
+	}
+	g.title = cmp.Or(block.Attributes["title"], g.title)
+	return g.draw(width), nil
+}
@@ -0,0 +1,89 @@
+package unidoc
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseGanttLoneDate(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, time.July, d, 0, 0, 0, 0, time.UTC) }
+	content := "Start: 2024-07-01\nWork: 2024-07-05"
+	tests := []struct {
+		timeline bool
+		want     []ganttTask
+	}{
+		// The second date ends a task in gantt charts and marks a point in
+		// timelines
+		{false, []ganttTask{
+			{label: "Start", start: day(1), end: day(1), milestone: true},
+			{label: "Work", start: day(1), end: day(5)},
+		}},
+		{true, []ganttTask{
+			{label: "Start", start: day(1), end: day(1), milestone: true},
+			{label: "Work", start: day(5), end: day(5), milestone: true},
+		}},
+	}
+	for _, tt := range tests {
+		g, err := parseGantt(content, tt.timeline)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(g.tasks) != len(tt.want) {
+			t.Fatalf("timeline %t: got %d tasks, want %d", tt.timeline, len(g.tasks), len(tt.want))
+		}
+		for i, task := range g.tasks {
+			if task != tt.want[i] {
+				t.Errorf("timeline %t: task %d = %+v, want %+v", tt.timeline, i, task, tt.want[i])
+			}
+		}
+	}
+}
+
+func TestGantt(t *testing.T) {
+	tests := []struct {
+		name, language, content string
+		attrs                   map[string]string
+		width                   int
+		want                    string
+	}{
+		{"sections", "gantt", "section Backend\nDesign API: design, 2024-07-01, 10d\nImplement: after design, 3w\nLaunch: milestone, 2024-08-15", map[string]string{"title": "Q3 plan"}, 40, `
+𝗤𝟯 𝗽𝗹𝗮𝗻
+𝗕𝗮𝗰𝗸𝗲𝗻𝗱      │
+  Design API │██████
+  Implement  │      ████████████
+  Launch     │                         ◆
+             └┴───────┴───────┴───────┴─
+              Jul 1   Jul 15  Jul 29`},
+		{"done", "gantt", "title Plan\nA: done, 2024-07-01, 2024-07-05\nB: 3d", nil, 30, `
+𝗣𝗹𝗮𝗻
+A │▒▒▒▒▒▒▒▒▒▒▒▒▒▒▒
+B │               ████████████
+  └┴───────┴──────┴───────┴───
+   Jul 1   Jul 3  Jul 5  Jul 7`},
+		{"lone start date", "gantt", "Kickoff: 2024-07-01\nWork: 5d", nil, 30, `
+Kickoff │◆
+Work    │█████████████████████
+        └┴───────┴────────┴───
+         Jul 1   Jul 3   Jul 5`},
+		{"timeline", "timeline", "Alpha: 2024-01-15\nBeta: 2024-03-01\nRelease: 2024-06-01", nil, 40, `
+Alpha   │◆
+Beta    │          ◆
+Release │                              ◆
+        └────┴─────┴──────┴──────┴──────
+             Feb 2024     Apr    May`},
+		{"mermaid", "mermaid", "gantt\n  dateFormat YYYY-MM-DD\n  A: 2024-07-01, 7d\n  B: 7d", nil, 30, `
+A │██████████████
+B │              █████████████
+  └┴─────────────┴────────────
+   Jul 1         Jul 8`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := processTest(t, FencedBlock{Language: tt.language, Content: tt.content, Attributes: tt.attrs}, tt.width)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkOutput(t, got, tt.want)
+		})
+	}
+}
@@ -5,3 +5,5 @@ go 1.24.4
 require github.com/yuin/goldmark v1.7.12
 
 require github.com/spf13/pflag v1.0.6
+
+require gopkg.in/yaml.v3 v3.0.1
@@ -0,0 +1,42 @@
+package unidoc
+
+import (
+	"strings"
+	"testing"
+)
+
+// convertTest converts input with the default configuration at a width of 30
+// columns, changed by the given functions.
+func convertTest(t *testing.T, input string, options ...func(*Config)) string {
+	t.Helper()
+	config := DefaultConfig()
+	config.Width = 30
+	for _, option := range options {
+		option(&config)
+	}
+	output, err := Convert([]byte(input), config)
+	if err != nil {
+		t.Fatalf("Convert(%q): %v", input, err)
+	}
+	return output
+}
+
+// checkOutput compares output with the expected output, written as a raw
+// string literal starting on the line after the backquote.
+func checkOutput(t *testing.T, got, want string) {
+	t.Helper()
+	if want = strings.TrimPrefix(want, "\n"); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+// processTest renders a block with the default processor of its language.
+func processTest(t *testing.T, block FencedBlock, width int) (string, error) {
+	t.Helper()
+	processor, ok := DefaultConfig().BlockProcessors[block.Language]
+	if !ok {
+		t.Fatalf("no processor for %s", block.Language)
+	}
+	lines, err := processor.ProcessBlock(block, width)
+	return strings.Join(lines, "\n"), err
+}
@@ -0,0 +1,177 @@
+package unidoc
+
+import (
+	"cmp"
+	"image"
+	_ "image/gif" // Decoders for image.Decode
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"math"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// quadrantChars maps the inked quadrants of a cell to quadrant blocks, with
+// bits for the upper left, upper right, lower left and lower right.
+var quadrantChars = []rune(" ▘▝▀▖▌▞▛▗▚▐▜▄▙▟█")
+
+// brailleDots holds the bits of the dots of a braille pattern by row and
+// column.
+var brailleDots = [4][2]rune{{0x01, 0x08}, {0x02, 0x10}, {0x04, 0x20}, {0x40, 0x80}}
+
+// maxImagePixels limits the size of the images that are decoded, so that a
+// small file declaring a huge image cannot take up the memory.
+const maxImagePixels = 1 << 24
+
+// loadLocalImage decodes the PNG, JPEG or GIF file a destination points to,
+// relative to dir. Paths cannot leave dir, not even through symbolic links.
+// It returns false for remote URLs, for paths outside of dir and for files
+// that are missing, too large or do not decode.
+func loadLocalImage(destination, dir string) (image.Image, bool) {
+	if strings.Contains(destination, "://") || strings.HasPrefix(destination, "//") ||
+		strings.HasPrefix(destination, "data:") {
+		return nil, false
+	}
+	path, err := url.PathUnescape(destination)
+	if err != nil {
+		path = destination
+	}
+
+	f, err := os.OpenInRoot(cmp.Or(dir, "."), path)
+	if err != nil {
+		return nil, false
+	}
+	defer f.Close()
+	config, _, err := image.DecodeConfig(f)
+	if err != nil || config.Width*config.Height > maxImagePixels {
+		return nil, false
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, false
+	}
+	img, _, err := image.Decode(f)
+	return img, err == nil
+}
+
+// imageLuminance scales an image to columns×rows pixels, averaging the pixels
+// covered by each, and returns their luminance from 0 for black to 1 for
+// white. Transparent pixels count as white.
+func imageLuminance(img image.Image, columns, rows int) [][]float64 {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	pixels := make([][]float64, rows)
+	for y := range rows {
+		pixels[y] = make([]float64, columns)
+		y0 := bounds.Min.Y + y*h/rows
+		y1 := max(bounds.Min.Y+(y+1)*h/rows, y0+1)
+		for x := range columns {
+			x0 := bounds.Min.X + x*w/columns
+			x1 := max(bounds.Min.X+(x+1)*w/columns, x0+1)
+			sum := 0.0
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					// Colors are premultiplied, so white shows through
+					// where alpha is missing
+					r, g, b, a := img.At(sx, sy).RGBA()
+					sum += (0.2126*float64(r)+0.7152*float64(g)+0.0722*float64(b))/0xffff + 1 - float64(a)/0xffff
+				}
+			}
+			pixels[y][x] = sum / float64((y1-y0)*(x1-x0))
+		}
+	}
+	return pixels
+}
+
+// ditherImage turns luminance into ink for the dark pixels, spreading the
+// error of every pixel to its neighbors by Floyd–Steinberg dithering.
+func ditherImage(pixels [][]float64) [][]bool {
+	ink := make([][]bool, len(pixels))
+	for y, row := range pixels {
+		ink[y] = make([]bool, len(row))
+		for x, value := range row {
+			ink[y][x] = value < 0.5
+			spread := value // Error of black ink, or of white paper below
+			if !ink[y][x] {
+				spread = value - 1
+			}
+			if x+1 < len(row) {
+				row[x+1] += spread * 7 / 16
+			}
+			if y+1 < len(pixels) {
+				below := pixels[y+1]
+				if x > 0 {
+					below[x-1] += spread * 3 / 16
+				}
+				below[x] += spread * 5 / 16
+				if x+1 < len(below) {
+					below[x+1] += spread * 1 / 16
+				}
+			}
+		}
+	}
+	return ink
+}
+
+// drawImage draws an image in a style at most columns wide, keeping its
+// aspect ratio with cells twice as tall as wide.
+func drawImage(img image.Image, style ImageStyle, columns int) []string {
+	cellWidth, cellHeight := 2, 4
+	switch style {
+	case ImageStyleBlocks:
+		cellWidth, cellHeight = 1, 2
+	case ImageStyleQuadrants:
+		cellWidth, cellHeight = 2, 2
+	}
+
+	bounds := img.Bounds()
+	columns = max(min(columns, bounds.Dx()/cellWidth), 1)
+	rows := max(int(math.Round(float64(columns)*float64(bounds.Dy())/float64(bounds.Dx())/2)), 1)
+	ink := ditherImage(imageLuminance(img, columns*cellWidth, rows*cellHeight))
+
+	lines := make([]string, rows)
+	for row := range rows {
+		var b strings.Builder
+		for column := range columns {
+			inked := func(dx, dy int) bool {
+				return ink[row*cellHeight+dy][column*cellWidth+dx]
+			}
+			switch style {
+			case ImageStyleBlocks:
+				bits := 0
+				if inked(0, 0) {
+					bits |= 3
+				}
+				if inked(0, 1) {
+					bits |= 12
+				}
+				b.WriteRune(quadrantChars[bits])
+			case ImageStyleQuadrants:
+				bits := 0
+				for i, dot := range [][2]int{{0, 0}, {1, 0}, {0, 1}, {1, 1}} {
+					if inked(dot[0], dot[1]) {
+						bits |= 1 << i
+					}
+				}
+				b.WriteRune(quadrantChars[bits])
+			default:
+				pattern := rune(0)
+				for dy, dots := range brailleDots {
+					for dx, dot := range dots {
+						if inked(dx, dy) {
+							pattern |= dot
+						}
+					}
+				}
+				if pattern == 0 {
+					b.WriteByte(' ')
+				} else {
+					b.WriteRune(0x2800 + pattern)
+				}
+			}
+		}
+		lines[row] = strings.TrimRight(b.String(), " ")
+	}
+	return lines
+}
@@ -0,0 +1,156 @@
+package unidoc
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// diagonalImage returns an 8×8 image that is black below its diagonal.
+func diagonalImage() *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, 8, 8))
+	for y := range 8 {
+		for x := range 8 {
+			if x >= y {
+				img.SetGray(x, y, color.Gray{Y: 255})
+			}
+		}
+	}
+	return img
+}
+
+func TestDrawImage(t *testing.T) {
+	gray := image.NewGray(image.Rect(0, 0, 4, 4))
+	for i := range gray.Pix {
+		gray.Pix[i] = 128
+	}
+
+	tests := []struct {
+		name    string
+		img     image.Image
+		style   ImageStyle
+		columns int
+		want    []string
+	}{
+		{"blocks", diagonalImage(), ImageStyleBlocks, 30, []string{"▄", "██▄", "████▄", "██████▄"}},
+		{"quadrants", diagonalImage(), ImageStyleQuadrants, 30, []string{"▄▖", "██▄▖"}},
+		{"braille", diagonalImage(), ImageStyleBraille, 30, []string{"⣦⡀", "⣿⣿⣦⡀"}},
+		{"scaled", diagonalImage(), ImageStyleBlocks, 2, []string{"▄▄"}},
+		{"dithered gray", gray, ImageStyleQuadrants, 30, []string{"▞▞"}},
+		{"transparent", image.NewRGBA(image.Rect(0, 0, 4, 4)), ImageStyleBlocks, 30, []string{"", ""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := drawImage(tt.img, tt.style, tt.columns); !slices.Equal(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestImage(t *testing.T) {
+	dir := t.TempDir()
+	writePNG(t, filepath.Join(dir, "diagonal.png"))
+
+	tests := []struct {
+		name, input string
+		style       ImageStyle
+		width       int
+		want        string
+	}{
+		{"blocks", "Before\n\n![Diagonal](diagonal.png)\n\nAfter", ImageStyleBlocks, 0, `
+Before
+
+▄
+██▄
+████▄
+██████▄
+🖼️  Diagonal
+
+After`},
+		{"image width", "![Diagonal](diagonal.png)", ImageStyleQuadrants, 2, `
+▙▖
+🖼️  Diagonal`},
+		{"missing file", "![Gone](missing.png \"Missing\")", ImageStyleBlocks, 0, `
+🖼️  Missing <missing.png>`},
+		{"text style", "![Diagonal](diagonal.png \"Title\")", ImageStyleText, 0, `
+🖼️  Title <diagonal.png>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := convertTest(t, tt.input, func(c *Config) {
+				c.ImageStyle = tt.style
+				c.ImageDir = dir
+				c.ImageWidth = tt.width
+			})
+			checkOutput(t, got, tt.want)
+		})
+	}
+}
+
+func TestLoadLocalImage(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "docs")
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writePNG(t, filepath.Join(root, "outside.png"))
+	writePNG(t, filepath.Join(dir, "inside.png"))
+	if err := os.Symlink(filepath.Join(root, "outside.png"), filepath.Join(dir, "link.png")); err != nil {
+		t.Fatal(err)
+	}
+
+	// A GIF declaring a screen of 65535×65535 pixels
+	var b bytes.Buffer
+	if err := gif.Encode(&b, image.NewPaletted(image.Rect(0, 0, 1, 1), color.Palette{color.White}), nil); err != nil {
+		t.Fatal(err)
+	}
+	huge := b.Bytes()
+	copy(huge[6:10], []byte{0xff, 0xff, 0xff, 0xff})
+	if err := os.WriteFile(filepath.Join(dir, "huge.gif"), huge, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := loadLocalImage("inside.png", dir); !ok {
+		t.Error("image inside the directory not loaded")
+	}
+	for _, destination := range []string{
+		"https://example.com/a.png", "//example.com/a.png", "data:image/png;base64,AA==", "missing.png",
+		"../outside.png", filepath.Join(root, "outside.png"), "link.png", "huge.gif",
+	} {
+		if _, ok := loadLocalImage(destination, dir); ok {
+			t.Errorf("loadLocalImage(%q) loaded an image", destination)
+		}
+	}
+}
+
+// writePNG writes the diagonal image to a PNG file.
+func writePNG(t *testing.T, path string) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := png.Encode(f, diagonalImage()); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestImageStyleFromDocument(t *testing.T) {
+	dir := t.TempDir()
+	writePNG(t, filepath.Join(dir, "diagonal.png"))
+	got := convertTest(t, "---\nunidoc:\n  image-style: blocks\n---\n\n![Diagonal](diagonal.png \"Title\")", func(c *Config) {
+		c.ImageDir = dir
+	})
+	checkOutput(t, got, `
+🖼️  Title <diagonal.png>`)
+}
@@ -0,0 +1,536 @@
+package unidoc
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// mathKind identifies the kind of a parsed LaTeX atom.
+type mathKind int
+
+const (
+	mathText     mathKind = iota // Literal text or a resolved symbol
+	mathGroup                    // Braced group: {...}
+	mathFrac                     // Fraction: \frac{num}{den}
+	mathSqrt                     // Root: \sqrt[index]{radicand}
+	mathFont                     // Font switch: \mathbb{...}, \mathcal{...}
+	mathOperator                 // Large operator taking limits: \sum, \int, \lim
+	mathFenced                   // Stretched delimiters: \left( ... \right)
+	mathMatrix                   // Environment: \begin{pmatrix} a & b \\ c & d \end{pmatrix}
+)
+
+// mathExpr is a sequence of LaTeX atoms.
+type mathExpr []*mathAtom
+
+// mathAtom is a single element of a LaTeX formula with its attached scripts.
+type mathAtom struct {
+	kind mathKind
+	text string       // Text of the atom, font name, root index, or environment name
+	args []mathExpr   // Group content, numerator and denominator, or radicand
+	rows [][]mathExpr // Cells of a matrix environment
+
+	open, close string // Delimiters of a fenced expression or matrix
+
+	sup, sub mathExpr // Attached superscript and subscript, nil if absent
+}
+
+// mathSymbols maps LaTeX commands to Unicode symbols.
+var mathSymbols = map[string]string{
+	// Greek letters
+	"alpha": "α", "beta": "β", "gamma": "γ", "delta": "δ", "epsilon": "ϵ", "varepsilon": "ε",
+	"zeta": "ζ", "eta": "η", "theta": "θ", "vartheta": "ϑ", "iota": "ι", "kappa": "κ",
+	"lambda": "λ", "mu": "μ", "nu": "ν", "xi": "ξ", "omicron": "ο", "pi": "π", "varpi": "ϖ",
+	"rho": "ρ", "varrho": "ϱ", "sigma": "σ", "varsigma": "ς", "tau": "τ", "upsilon": "υ",
+	"phi": "ϕ", "varphi": "φ", "chi": "χ", "psi": "ψ", "omega": "ω",
+	"Gamma": "Γ", "Delta": "Δ", "Theta": "Θ", "Lambda": "Λ", "Xi": "Ξ", "Pi": "Π",
+	"Sigma": "Σ", "Upsilon": "Υ", "Phi": "Φ", "Psi": "Ψ", "Omega": "Ω",
+
+	// Relations
+	"le": "≤", "leq": "≤", "ge": "≥", "geq": "≥", "ne": "≠", "neq": "≠", "ll": "≪", "gg": "≫",
+	"approx": "≈", "equiv": "≡", "sim": "∼", "simeq": "≃", "cong": "≅", "propto": "∝",
+	"in": "∈", "notin": "∉", "ni": "∋", "subset": "⊂", "subseteq": "⊆", "supset": "⊃",
+	"supseteq": "⊇", "perp": "⊥", "parallel": "∥", "mid": "∣", "vdash": "⊢", "models": "⊨",
+
+	// Arrows
+	"to": "→", "rightarrow": "→", "leftarrow": "←", "gets": "←", "leftrightarrow": "↔",
+	"Rightarrow": "⇒", "Leftarrow": "⇐", "Leftrightarrow": "⇔", "implies": "⟹", "iff": "⟺",
+	"mapsto": "↦", "uparrow": "↑", "downarrow": "↓",
+
+	// Binary operators
+	"times": "×", "div": "÷", "pm": "±", "mp": "∓", "cdot": "⋅", "ast": "∗", "star": "⋆",
+	"circ": "∘", "bullet": "∙", "oplus": "⊕", "otimes": "⊗", "cup": "∪", "cap": "∩",
+	"setminus": "∖", "land": "∧", "wedge": "∧", "lor": "∨", "vee": "∨",
+
+	// Miscellaneous symbols
+	"infty": "∞", "partial": "∂", "nabla": "∇", "forall": "∀", "exists": "∃", "nexists": "∄",
+	"emptyset": "∅", "varnothing": "∅", "neg": "¬", "lnot": "¬", "angle": "∠", "prime": "′",
+	"hbar": "ℏ", "ell": "ℓ", "Re": "ℜ", "Im": "ℑ", "aleph": "ℵ", "top": "⊤", "bot": "⊥",
+	"therefore": "∴", "because": "∵", "triangle": "△", "square": "□", "degree": "°",
+	"ldots": "…", "dots": "…", "cdots": "⋯", "vdots": "⋮", "ddots": "⋱",
+
+	// Delimiters
+	"langle": "⟨", "rangle": "⟩", "lceil": "⌈", "rceil": "⌉", "lfloor": "⌊", "rfloor": "⌋",
+	"{": "{", "}": "}", "|": "‖", "$": "$", "%": "%", "&": "&", "#": "#", "_": "_",
+
+	// Spacing
+	",": " ", ";": " ", ":": " ", " ": " ", "quad": "  ", "qquad": "    ", "!": "",
+}
+
+// mathOperators maps LaTeX commands of large operators to Unicode symbols.
+var mathOperators = map[string]string{
+	"sum": "∑", "prod": "∏", "coprod": "∐", "int": "∫", "iint": "∬", "iiint": "∭",
+	"oint": "∮", "bigcup": "⋃", "bigcap": "⋂", "bigoplus": "⨁", "bigotimes": "⨂",
+	"lim": "lim", "max": "max", "min": "min", "sup": "sup", "inf": "inf",
+}
+
+// mathFunctions lists LaTeX commands of named functions rendered upright.
+var mathFunctions = map[string]bool{
+	"sin": true, "cos": true, "tan": true, "cot": true, "sec": true, "csc": true,
+	"arcsin": true, "arccos": true, "arctan": true, "sinh": true, "cosh": true, "tanh": true,
+	"log": true, "ln": true, "lg": true, "exp": true, "det": true, "dim": true, "ker": true,
+	"gcd": true, "deg": true, "arg": true, "Pr": true,
+}
+
+// mathFonts maps LaTeX font commands to text conversions.
+var mathFonts = map[string]func(string) string{
+	"mathbb":     toDoubleStruckText,
+	"mathcal":    toItalicScriptText,
+	"mathscr":    toItalicScriptText,
+	"mathbf":     toBoldSansSerifText,
+	"boldsymbol": toBoldSansSerifText,
+	"mathit":     toSlantedSansSerifText,
+	"mathrm":     func(s string) string { return s },
+	"mathsf":     func(s string) string { return s },
+}
+
+// mathDelimiterSizes lists commands that size delimiters; they are dropped
+// since plain text has only one size.
+var mathDelimiterSizes = map[string]bool{
+	"big": true, "Big": true, "bigg": true, "Bigg": true,
+	"bigl": true, "bigr": true, "Bigl": true, "Bigr": true,
+}
+
+// mathEnvironments maps matrix-like environments to their delimiters.
+var mathEnvironments = map[string][2]string{
+	"matrix":  {"", ""},
+	"pmatrix": {"(", ")"},
+	"bmatrix": {"[", "]"},
+	"Bmatrix": {"{", "}"},
+	"vmatrix": {"|", "|"},
+	"Vmatrix": {"‖", "‖"},
+	"cases":   {"{", ""},
+	"aligned": {"", ""},
+	"align":   {"", ""},
+	"array":   {"", ""},
+}
+
+// mathParser is a recursive descent parser for a practical LaTeX subset.
+type mathParser struct {
+	src       string
+	pos       int
+	leftDepth int // Number of open \left delimiters
+}
+
+// parseLatex parses a LaTeX formula.
+func parseLatex(src string) mathExpr {
+	p := &mathParser{src: src}
+	var expr mathExpr
+	for p.pos < len(p.src) {
+		expr = append(expr, p.parseExpr()...)
+		if p.pos < len(p.src) {
+			// Keep unbalanced closing braces as text
+			expr = append(expr, &mathAtom{kind: mathText, text: "}"})
+			p.pos++
+		}
+	}
+	return expr
+}
+
+// parseExpr parses atoms up to the end of the input, a closing brace, or the
+// \right matching an open \left.
+func (p *mathParser) parseExpr() mathExpr {
+	var expr mathExpr
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch {
+		case c == '}':
+			return expr
+
+		case p.leftDepth > 0 && strings.HasPrefix(p.src[p.pos:], `\right`):
+			return expr
+
+		case c == '^' || c == '_':
+			p.pos++
+			// Scripts attach to the preceding atom, ignoring whitespace
+			if n := len(expr); n > 0 && expr[n-1].kind == mathText && strings.TrimSpace(expr[n-1].text) == "" {
+				expr = expr[:n-1]
+			}
+			if len(expr) == 0 {
+				expr = append(expr, &mathAtom{kind: mathText})
+			}
+			last := expr[len(expr)-1]
+			arg := p.parseArg()
+			if c == '^' {
+				last.sup = append(last.sup, arg...)
+			} else {
+				last.sub = append(last.sub, arg...)
+			}
+
+		case unicode.IsSpace(rune(c)):
+			for p.pos < len(p.src) && unicode.IsSpace(rune(p.src[p.pos])) {
+				p.pos++
+			}
+			expr = append(expr, &mathAtom{kind: mathText, text: " "})
+
+		default:
+			if atom := p.parseAtom(); atom != nil {
+				expr = append(expr, atom)
+			}
+		}
+	}
+	return expr
+}
+
+// parseAtom parses a single atom: a group, a command or a character.
+func (p *mathParser) parseAtom() *mathAtom {
+	c := p.src[p.pos]
+	switch c {
+	case '{':
+		p.pos++
+		content := p.parseExpr()
+		if p.pos < len(p.src) {
+			p.pos++ // closing brace
+		}
+		return &mathAtom{kind: mathGroup, args: []mathExpr{content}}
+
+	case '\\':
+		return p.parseCommand()
+	}
+
+	r, size := utf8.DecodeRuneInString(p.src[p.pos:])
+	p.pos += size
+	switch r {
+	case '-':
+		return &mathAtom{kind: mathText, text: "−"}
+	case '*':
+		return &mathAtom{kind: mathText, text: "∗"}
+	case '\'':
+		return &mathAtom{kind: mathText, text: "′"}
+	}
+	return &mathAtom{kind: mathText, text: string(r)}
+}
+
+// parseArg parses the argument of a command or script: a braced group or a
+// single atom.
+func (p *mathParser) parseArg() mathExpr {
+	for p.pos < len(p.src) && p.src[p.pos] == ' ' {
+		p.pos++
+	}
+	if p.pos >= len(p.src) || p.src[p.pos] == '}' {
+		return mathExpr{}
+	}
+	atom := p.parseAtom()
+	if atom == nil {
+		return mathExpr{}
+	}
+	if atom.kind == mathGroup && atom.sup == nil && atom.sub == nil {
+		return atom.args[0]
+	}
+	return mathExpr{atom}
+}
+
+// parseRawGroup returns the verbatim content of a braced group.
+func (p *mathParser) parseRawGroup() (string, bool) {
+	for p.pos < len(p.src) && p.src[p.pos] == ' ' {
+		p.pos++
+	}
+	if p.pos >= len(p.src) || p.src[p.pos] != '{' {
+		return "", false
+	}
+	depth := 0
+	for i := p.pos; i < len(p.src); i++ {
+		switch p.src[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				content := p.src[p.pos+1 : i]
+				p.pos = i + 1
+				return content, true
+			}
+		}
+	}
+	return "", false
+}
+
+// parseDelimiter parses the delimiter following \left or \right, where "."
+// stands for an invisible delimiter.
+func (p *mathParser) parseDelimiter() string {
+	for p.pos < len(p.src) && p.src[p.pos] == ' ' {
+		p.pos++
+	}
+	if p.pos >= len(p.src) {
+		return ""
+	}
+	if p.src[p.pos] == '.' {
+		p.pos++
+		return ""
+	}
+	atom := p.parseAtom()
+	if atom == nil || atom.kind != mathText {
+		return ""
+	}
+	return atom.text
+}
+
+// parseEnvironment parses the body of a \begin{env} ... \end{env} block into
+// rows of cells separated by \\ and &.
+func (p *mathParser) parseEnvironment(env string) *mathAtom {
+	delims, ok := mathEnvironments[env]
+	if !ok {
+		delims = mathEnvironments["matrix"]
+	}
+	if env == "array" {
+		// Skip the column specification
+		p.parseRawGroup()
+	}
+
+	endTag := `\end{` + env + `}`
+	body := p.src[p.pos:]
+	if end := strings.Index(body, endTag); end >= 0 {
+		body = body[:end]
+		p.pos += end + len(endTag)
+	} else {
+		p.pos = len(p.src)
+	}
+
+	atom := &mathAtom{kind: mathMatrix, text: env, open: delims[0], close: delims[1]}
+	for _, row := range splitTopLevel(body, `\\`) {
+		if strings.TrimSpace(row) == "" {
+			continue
+		}
+		var cells []mathExpr
+		for _, cell := range splitTopLevel(row, "&") {
+			cells = append(cells, parseLatex(strings.TrimSpace(cell)))
+		}
+		atom.rows = append(atom.rows, cells)
+	}
+	return atom
+}
+
+// splitTopLevel splits LaTeX source at separators outside of braces and
+// nested environments.
+func splitTopLevel(src, sep string) []string {
+	var (
+		parts []string
+		depth int
+		start int
+	)
+	for i := 0; i < len(src); i++ {
+		switch {
+		case src[i] == '\\' && strings.HasPrefix(src[i:], `\begin{`):
+			depth++
+		case src[i] == '\\' && strings.HasPrefix(src[i:], `\end{`):
+			depth--
+		case src[i] == '{':
+			depth++
+		case src[i] == '}':
+			depth--
+		case depth == 0 && strings.HasPrefix(src[i:], sep):
+			parts = append(parts, src[start:i])
+			i += len(sep) - 1
+			start = i + 1
+			continue
+		}
+		if src[i] == '\\' && i+1 < len(src) && !isASCIILetter(src[i+1]) && !strings.HasPrefix(src[i:], sep) {
+			i++ // skip escaped character such as \{ or \&
+		}
+	}
+	return append(parts, src[start:])
+}
+
+// parseOptional returns the content of an optional [...] argument.
+func (p *mathParser) parseOptional() string {
+	if p.pos >= len(p.src) || p.src[p.pos] != '[' {
+		return ""
+	}
+	end := strings.IndexByte(p.src[p.pos:], ']')
+	if end < 0 {
+		return ""
+	}
+	opt := p.src[p.pos+1 : p.pos+end]
+	p.pos += end + 1
+	return strings.TrimSpace(opt)
+}
+
+// parseCommand parses a command starting with a backslash.
+func (p *mathParser) parseCommand() *mathAtom {
+	start := p.pos
+	p.pos++ // backslash
+	if p.pos >= len(p.src) {
+		return &mathAtom{kind: mathText, text: "\\"}
+	}
+
+	end := p.pos
+	for end < len(p.src) && isASCIILetter(p.src[end]) {
+		end++
+	}
+	if end == p.pos {
+		// Single character commands such as \, or \{
+		_, size := utf8.DecodeRuneInString(p.src[p.pos:])
+		end = p.pos + size
+	}
+	name := p.src[p.pos:end]
+	p.pos = end
+
+	if sym, ok := mathSymbols[name]; ok {
+		return &mathAtom{kind: mathText, text: sym}
+	}
+	if op, ok := mathOperators[name]; ok {
+		return &mathAtom{kind: mathOperator, text: op}
+	}
+	if mathFunctions[name] {
+		return &mathAtom{kind: mathText, text: name}
+	}
+	if _, ok := mathFonts[name]; ok {
+		return &mathAtom{kind: mathFont, text: name, args: []mathExpr{p.parseArg()}}
+	}
+	if mathDelimiterSizes[name] {
+		return nil
+	}
+
+	switch name {
+	case "left":
+		open := p.parseDelimiter()
+		p.leftDepth++
+		inner := p.parseExpr()
+		p.leftDepth--
+		var closeDelim string
+		if strings.HasPrefix(p.src[p.pos:], `\right`) {
+			p.pos += len(`\right`)
+			closeDelim = p.parseDelimiter()
+		}
+		return &mathAtom{kind: mathFenced, args: []mathExpr{inner}, open: open, close: closeDelim}
+
+	case "right":
+		// Unbalanced \right, keep only its delimiter
+		return &mathAtom{kind: mathText, text: p.parseDelimiter()}
+
+	case "begin":
+		if env, ok := p.parseRawGroup(); ok {
+			return p.parseEnvironment(env)
+		}
+
+	case "frac", "dfrac", "tfrac":
+		num := p.parseArg()
+		den := p.parseArg()
+		return &mathAtom{kind: mathFrac, args: []mathExpr{num, den}}
+
+	case "sqrt":
+		index := p.parseOptional()
+		return &mathAtom{kind: mathSqrt, text: index, args: []mathExpr{p.parseArg()}}
+
+	case "text", "textrm", "mbox", "operatorname":
+		if content, ok := p.parseRawGroup(); ok {
+			return &mathAtom{kind: mathText, text: content}
+		}
+	}
+
+	// Keep unsupported commands, including their arguments, as source text
+	for p.pos < len(p.src) && p.src[p.pos] == '{' {
+		if _, ok := p.parseRawGroup(); !ok {
+			break
+		}
+	}
+	return &mathAtom{kind: mathText, text: p.src[start:p.pos]}
+}
+
+// isASCIILetter reports whether c is an ASCII letter.
+func isASCIILetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// latexToUnicode converts a LaTeX formula to a single line of Unicode text.
+func latexToUnicode(src string) string {
+	return linearMath(parseLatex(src))
+}
+
+// linearMath renders a LaTeX expression on a single line.
+func linearMath(expr mathExpr) string {
+	var b strings.Builder
+	for _, atom := range expr {
+		b.WriteString(linearAtom(atom))
+	}
+	return b.String()
+}
+
+// linearAtom renders a LaTeX atom and its scripts on a single line.
+func linearAtom(a *mathAtom) string {
+	var s string
+	switch a.kind {
+	case mathText, mathOperator:
+		s = a.text
+	case mathGroup:
+		s = linearMath(a.args[0])
+	case mathFrac:
+		s = parenthesize(linearMath(a.args[0])) + "⁄" + parenthesize(linearMath(a.args[1]))
+	case mathSqrt:
+		s = rootSymbol(a.text) + parenthesize(linearMath(a.args[0]))
+	case mathFont:
+		s = mathFonts[a.text](linearMath(a.args[0]))
+	case mathFenced:
+		s = a.open + linearMath(a.args[0]) + a.close
+	case mathMatrix:
+		rows := make([]string, len(a.rows))
+		for i, row := range a.rows {
+			cells := make([]string, len(row))
+			for j, cell := range row {
+				cells[j] = linearMath(cell)
+			}
+			rows[i] = strings.Join(cells, " ")
+		}
+		s = a.open + strings.Join(rows, "; ") + a.close
+	}
+
+	if a.sub != nil {
+		s += scriptText(stripSpaces(linearMath(a.sub)), toSubscriptText, "_")
+	}
+	if a.sup != nil {
+		s += scriptText(stripSpaces(linearMath(a.sup)), toSuperscriptText, "^")
+	}
+	return s
+}
+
+// parenthesize wraps text in parentheses unless it is a single term.
+func parenthesize(s string) string {
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '.' {
+			return "(" + s + ")"
+		}
+	}
+	return s
+}
+
+// rootSymbol returns the radical sign for a root index.
+func rootSymbol(index string) string {
+	switch index {
+	case "", "2":
+		return "√"
+	case "3":
+		return "∛"
+	case "4":
+		return "∜"
+	}
+	if sup, ok := toSuperscriptText(index); ok {
+		return sup + "√"
+	}
+	return "^(" + index + ")√"
+}
+
+// stripSpaces removes the spaces from a rendered formula, which are
+// insignificant in scripts.
+func stripSpaces(s string) string {
+	return strings.ReplaceAll(s, " ", "")
+}
@@ -0,0 +1,40 @@
+package unidoc
+
+import "testing"
+
+func TestLatexToUnicode(t *testing.T) {
+	tests := []struct {
+		src, want string
+	}{
+		{`\alpha + \beta`, "α + β"},
+		{`x^2 + y_{i}`, "x² + yᵢ"},
+		{`x^{n+1}`, "xⁿ⁺¹"},
+		{`\frac{a}{b}`, "a⁄b"},
+		{`\frac{a+1}{2}`, "(a+1)⁄2"},
+		{`\sqrt{x}`, "√x"},
+		{`\sqrt[3]{x+1}`, "∛(x+1)"},
+		{`\mathbb{R}`, "ℝ"},
+		{`\mathcal{L}`, "ℒ"},
+		{`\sum_{i=1}^{n} i`, "∑ᵢ₌₁ⁿ i"},
+		{`\int_0^1 f(x)\,dx`, "∫₀¹ f(x) dx"},
+		{`\sin x \leq 1`, "sin x ≤ 1"},
+		{`\infty \to \emptyset`, "∞ → ∅"},
+		{`a \cdot b \times c`, "a ⋅ b × c"},
+		{`\left( x \right)`, "( x )"},
+		{`\begin{pmatrix} a & b \\ c & d \end{pmatrix}`, "(a b; c d)"},
+
+		// Scripts without a Unicode form for every character
+		{`e^{i\pi}`, "e^(iπ)"},
+
+		// Malformed input is kept or dropped, never fails
+		{`\unknown{x}`, `\unknown{x}`},
+		{`\frac{a`, "a⁄"},
+		{`x^`, "x"},
+		{`}`, "}"},
+	}
+	for _, tt := range tests {
+		if got := latexToUnicode(tt.src); got != tt.want {
+			t.Errorf("latexToUnicode(%q) = %q, want %q", tt.src, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package unidoc
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// defaultWidth is the default target width of the output in columns.
+const defaultWidth = 66
+
+// minWidth is the narrowest width nested content is laid out in.
+const minWidth = 12
+
+// runeWidth returns the number of terminal columns a rune occupies.
+func runeWidth(r rune) int {
+	switch {
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		// Combining marks, joiners and variation selectors take no space
+		return 0
+	case r >= 0x1100 && r <= 0x115F,
+		r >= 0x2E80 && r <= 0xA4CF,
+		r >= 0xAC00 && r <= 0xD7A3,
+		r >= 0xF900 && r <= 0xFAFF,
+		r >= 0xFE30 && r <= 0xFE4F,
+		r >= 0xFF00 && r <= 0xFF60,
+		r >= 0xFFE0 && r <= 0xFFE6,
+		r >= 0x1F300 && r <= 0x1F64F,
+		r >= 0x1F900 && r <= 0x1F9FF,
+		r >= 0x20000 && r <= 0x3FFFD:
+		// East Asian wide characters and emoji take two columns
+		return 2
+	default:
+		return 1
+	}
+}
+
+// textWidth returns the number of terminal columns a string occupies.
+func textWidth(s string) int {
+	width := 0
+	for _, r := range s {
+		width += runeWidth(r)
+	}
+	return width
+}
+
+// padRight pads a string with spaces up to the given width.
+func padRight(s string, width int) string {
+	if n := width - textWidth(s); n > 0 {
+		return s + strings.Repeat(" ", n)
+	}
+	return s
+}
+
+// centerText centers a string within the given width, padding both sides.
+func centerText(s string, width int) string {
+	left := max((width-textWidth(s))/2, 0)
+	return padRight(strings.Repeat(" ", left)+s, width)
+}
+
+// truncateText shortens a string to the given width, marking the cut with an ellipsis.
+func truncateText(s string, width int) string {
+	if textWidth(s) <= width {
+		return s
+	}
+	var b strings.Builder
+	used := 0
+	for _, r := range s {
+		w := runeWidth(r)
+		if used+w > width-1 {
+			break
+		}
+		b.WriteRune(r)
+		used += w
+	}
+	b.WriteString("…")
+	return b.String()
+}
+
+// hangingIndent returns the prefix that continuation lines of a wrapped line
+// start with, keeping them aligned with the text after indentation, blockquote
+// bars and list markers.
+func hangingIndent(line string) string {
+	rest := strings.TrimLeft(line, " ")
+	prefix := strings.Repeat(" ", len(line)-len(rest))
+
+	for strings.HasPrefix(rest, "┃ ") {
+		prefix += "┃ "
+		rest = rest[len("┃ "):]
+	}
+
+	// Treat a short leading token without letters or digits as a list marker
+	if marker, _, found := strings.Cut(rest, " "); found && marker != "" && textWidth(marker) <= 4 {
+		isMarker := true
+		for _, r := range marker {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				isMarker = false
+				break
+			}
+		}
+		if isMarker {
+			prefix += strings.Repeat(" ", textWidth(marker)+1)
+		}
+	}
+
+	return prefix
+}
+
+// wrapLine breaks a line into several lines no wider than width, reflowing
+// words and indenting continuation lines.
+func wrapLine(line string, width int) []string {
+	if textWidth(line) <= width {
+		return []string{line}
+	}
+
+	hanging := hangingIndent(line)
+	if textWidth(hanging) > width/2 {
+		hanging = ""
+	}
+	lead := line[:len(line)-len(strings.TrimLeft(line, " "))]
+
+	var (
+		lines   []string
+		current = lead
+		empty   = true
+	)
+	flush := func() {
+		lines = append(lines, strings.TrimRight(current, " "))
+		current = hanging
+		empty = true
+	}
+
+	for _, word := range strings.Fields(line) {
+		if !empty && textWidth(current)+1+textWidth(word) > width {
+			flush()
+		}
+		if !empty {
+			current += " "
+		}
+		// Hard-break words that cannot fit on a line of their own
+		for textWidth(current)+textWidth(word) > width {
+			head, tail := splitAtWidth(word, width-textWidth(current))
+			if head == "" {
+				_, size := utf8.DecodeRuneInString(word)
+				head, tail = word[:size], word[size:]
+			}
+			current += head
+			word = tail
+			flush()
+		}
+		if word != "" {
+			current += word
+			empty = false
+		}
+	}
+	if !empty {
+		flush()
+	}
+	return lines
+}
+
+// splitAtWidth splits a string after the last rune that fits into width.
+func splitAtWidth(s string, width int) (string, string) {
+	used := 0
+	for i, r := range s {
+		w := runeWidth(r)
+		if used+w > width {
+			return s[:i], s[i:]
+		}
+		used += w
+	}
+	return s, ""
+}
+
+// reflowText wraps every line of a rendered text block to the given width.
+func reflowText(text string, width int) []string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		lines = append(lines, wrapLine(line, width)...)
+	}
+	return lines
+}
+
+// drawBox draws a box of the given outer width around lines, with an optional
+// title embedded into the top border.
+func drawBox(lines []string, width int, title string, style BoxStyle) string {
+	c := style.chars()
+	inner := width - 4
+
+	var b strings.Builder
+
+	// Top border
+	b.WriteString(c.topLeft)
+	if title != "" {
+		title = truncateText(title, width-6)
+		b.WriteString(c.horizontal + " " + title + " ")
+		b.WriteString(strings.Repeat(c.horizontal, max(width-5-textWidth(title), 0)))
+	} else {
+		b.WriteString(strings.Repeat(c.horizontal, width-2))
+	}
+	b.WriteString(c.topRight + "\n")
+
+	// Content
+	for _, line := range lines {
+		b.WriteString(c.vertical + " " + padRight(truncateText(line, inner), inner) + " " + c.vertical + "\n")
+	}
+
+	// Bottom border
+	b.WriteString(c.bottomLeft + strings.Repeat(c.horizontal, width-2) + c.bottomRight + "\n")
+
+	return b.String()
+}
@@ -0,0 +1,146 @@
+package unidoc
+
+import (
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// A Highlight represents highlighted text: ==text==.
+type Highlight struct {
+	gast.BaseInline
+}
+
+// KindHighlight is a NodeKind of the Highlight node.
+var KindHighlight = gast.NewNodeKind("Highlight")
+
+// Kind implements Node.Kind.
+func (n *Highlight) Kind() gast.NodeKind {
+	return KindHighlight
+}
+
+// Dump implements Node.Dump.
+func (n *Highlight) Dump(source []byte, level int) {
+	gast.DumpHelper(n, source, level, nil, nil)
+}
+
+// An Inserted represents inserted text, shown underlined: ++text++.
+type Inserted struct {
+	gast.BaseInline
+}
+
+// KindInserted is a NodeKind of the Inserted node.
+var KindInserted = gast.NewNodeKind("Inserted")
+
+// Kind implements Node.Kind.
+func (n *Inserted) Kind() gast.NodeKind {
+	return KindInserted
+}
+
+// Dump implements Node.Dump.
+func (n *Inserted) Dump(source []byte, level int) {
+	gast.DumpHelper(n, source, level, nil, nil)
+}
+
+// markDelimiterProcessor matches pairs of doubled '=' or '+' delimiters.
+type markDelimiterProcessor struct {
+	char byte
+}
+
+func (p *markDelimiterProcessor) IsDelimiter(b byte) bool {
+	return b == p.char
+}
+
+func (p *markDelimiterProcessor) CanOpenCloser(opener, closer *parser.Delimiter) bool {
+	return opener.Char == closer.Char
+}
+
+func (p *markDelimiterProcessor) OnMatch(_ int) gast.Node {
+	if p.char == '=' {
+		return &Highlight{}
+	}
+	return &Inserted{}
+}
+
+type markParser struct {
+	processor *markDelimiterProcessor
+}
+
+// newMarkParser returns a new InlineParser that parses spans enclosed in a
+// doubled delimiter character, such as ==highlight== or ++inserted++.
+func newMarkParser(char byte) parser.InlineParser {
+	return &markParser{processor: &markDelimiterProcessor{char: char}}
+}
+
+func (s *markParser) Trigger() []byte {
+	return []byte{s.processor.char}
+}
+
+func (s *markParser) Parse(_ gast.Node, block text.Reader, pc parser.Context) gast.Node {
+	before := block.PrecendingCharacter()
+	line, segment := block.PeekLine()
+	node := parser.ScanDelimiter(line, before, 2, s.processor)
+	if node == nil || node.OriginalLength != 2 || before == rune(s.processor.char) {
+		return nil
+	}
+
+	node.Segment = segment.WithStop(segment.Start + node.OriginalLength)
+	block.Advance(node.OriginalLength)
+	pc.PushDelimiter(node)
+	return node
+}
+
+func (s *markParser) CloseBlock(_ gast.Node, _ parser.Context) {
+	// nothing to do
+}
+
+// Highlight renderer
+func (r *UnicodeRenderer) renderHighlight(
+	w util.BufWriter,
+	_ []byte,
+	_ gast.Node,
+	entering bool,
+) (gast.WalkStatus, error) {
+	r.inHighlight = entering
+
+	var marker string
+	switch r.config.HighlightStyle {
+	case HighlightStyleMarkers:
+		marker = "=="
+	case HighlightStyleBrackets:
+		marker = "【"
+		if !entering {
+			marker = "】"
+		}
+	}
+	if _, err := w.WriteString(marker); err != nil {
+		return gast.WalkStop, err
+	}
+	return gast.WalkContinue, nil
+}
+
+// Inserted renderer
+func (r *UnicodeRenderer) renderInserted(
+	w util.BufWriter,
+	_ []byte,
+	_ gast.Node,
+	entering bool,
+) (gast.WalkStatus, error) {
+	r.inUnderline = entering
+
+	var marker string
+	switch r.config.UnderlineStyle {
+	case UnderlineStyleMarkers:
+		marker = "++"
+	case UnderlineStyleBrackets:
+		marker = "【"
+		if !entering {
+			marker = "】"
+		}
+	}
+	if _, err := w.WriteString(marker); err != nil {
+		return gast.WalkStop, err
+	}
+	return gast.WalkContinue, nil
+}
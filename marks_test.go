@@ -0,0 +1,57 @@
+package unidoc
+
+import "testing"
+
+func TestMarkStyles(t *testing.T) {
+	tests := []struct {
+		style, want string
+	}{
+		{"plain", "hi there and new"},
+		{"markers", "==hi there== and ++new++"},
+		{"underline", "h̲i̲ ̲t̲h̲e̲r̲e̲ and n̲e̲w̲"},
+		{"double-underline", "h̳i̳ ̳t̳h̳e̳r̳e̳ and n̳e̳w̳"},
+		{"brackets", "【hi there】 and 【new】"},
+	}
+	for _, tt := range tests {
+		got := convertTest(t, "==hi there== and ++new++", func(c *Config) {
+			if err := c.HighlightStyle.Set(tt.style); err != nil {
+				t.Fatal(err)
+			}
+			if err := c.UnderlineStyle.Set(tt.style); err != nil {
+				t.Fatal(err)
+			}
+		})
+		if got != tt.want {
+			t.Errorf("style %s: got %q, want %q", tt.style, got, tt.want)
+		}
+	}
+}
+
+func TestMarks(t *testing.T) {
+	tests := []struct {
+		input, want string
+	}{
+		{"==**bold**==", "【𝗯𝗼𝗹𝗱】"},
+		{"== no ==", "== no =="},
+		{"a==b", "a==b"},
+		{"===x===", "===x==="},
+		{"++ x++", "++ x++"},
+		{"a ++ b", "a ++ b"},
+	}
+	for _, tt := range tests {
+		if got := convertTest(t, tt.input); got != tt.want {
+			t.Errorf("Convert(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestMarkStyleInvalid(t *testing.T) {
+	var highlight HighlightStyle
+	if err := highlight.Set("wavy"); err == nil {
+		t.Error("HighlightStyle.Set(wavy) succeeded")
+	}
+	var underline UnderlineStyle
+	if err := underline.Set("wavy"); err == nil {
+		t.Error("UnderlineStyle.Set(wavy) succeeded")
+	}
+}
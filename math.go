@@ -0,0 +1,232 @@
+package unidoc
+
+import (
+	"bytes"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// An InlineMath represents a LaTeX formula in a paragraph: $x^2$.
+type InlineMath struct {
+	gast.BaseInline
+	Formula string // LaTeX source between the dollar signs
+}
+
+// KindInlineMath is a NodeKind of the InlineMath node.
+var KindInlineMath = gast.NewNodeKind("InlineMath")
+
+// Kind implements Node.Kind.
+func (n *InlineMath) Kind() gast.NodeKind {
+	return KindInlineMath
+}
+
+// Dump implements Node.Dump.
+func (n *InlineMath) Dump(source []byte, level int) {
+	gast.DumpHelper(n, source, level, map[string]string{
+		"Formula": n.Formula,
+	}, nil)
+}
+
+type inlineMathParser struct{}
+
+// newInlineMathParser returns a new InlineParser that parses $...$ formulas.
+func newInlineMathParser() parser.InlineParser {
+	return &inlineMathParser{}
+}
+
+func (s *inlineMathParser) Trigger() []byte {
+	return []byte{'$'}
+}
+
+// Parse follows the Pandoc rules: the opening $ must be followed by a
+// non-space character, and the closing $ must be preceded by a non-space
+// character and not be followed by a digit, so that "$5 and $10" stays text.
+func (s *inlineMathParser) Parse(_ gast.Node, block text.Reader, _ parser.Context) gast.Node {
+	line, _ := block.PeekLine()
+	if len(line) < 3 || line[1] == '$' || isSpaceByte(line[1]) {
+		return nil
+	}
+
+	for i := 2; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++ // skip escaped character
+		case '$':
+			if isSpaceByte(line[i-1]) {
+				continue
+			}
+			if r, _ := utf8.DecodeRune(line[i+1:]); unicode.IsDigit(r) {
+				continue
+			}
+			node := &InlineMath{Formula: string(line[1:i])}
+			block.Advance(i + 1)
+			return node
+		}
+	}
+	return nil
+}
+
+// isSpaceByte reports whether c is an ASCII whitespace character.
+func isSpaceByte(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+// InlineMath renderer
+func (r *UnicodeRenderer) renderInlineMath(
+	w util.BufWriter,
+	_ []byte,
+	node gast.Node,
+	entering bool,
+) (gast.WalkStatus, error) {
+	if entering {
+		n := node.(*InlineMath)
+		if _, err := w.WriteString(latexToUnicode(n.Formula)); err != nil {
+			return gast.WalkStop, err
+		}
+	}
+	return gast.WalkSkipChildren, nil
+}
+
+// A DisplayMath represents a LaTeX formula block:
+//
+//	$$
+//	\sum_{i=1}^{n} i = \frac{n(n+1)}{2}
+//	$$
+type DisplayMath struct {
+	gast.BaseBlock
+	closed bool // Whether the closing $$ has been seen
+}
+
+// KindDisplayMath is a NodeKind of the DisplayMath node.
+var KindDisplayMath = gast.NewNodeKind("DisplayMath")
+
+// Kind implements Node.Kind.
+func (n *DisplayMath) Kind() gast.NodeKind {
+	return KindDisplayMath
+}
+
+// IsRaw implements Node.IsRaw.
+func (n *DisplayMath) IsRaw() bool {
+	return true
+}
+
+// Dump implements Node.Dump.
+func (n *DisplayMath) Dump(source []byte, level int) {
+	gast.DumpHelper(n, source, level, nil, nil)
+}
+
+// Formula returns the LaTeX source of the block.
+func (n *DisplayMath) Formula(source []byte) string {
+	var b strings.Builder
+	for i := 0; i < n.Lines().Len(); i++ {
+		line := n.Lines().At(i)
+		b.Write(line.Value(source))
+	}
+	return b.String()
+}
+
+type displayMathParser struct{}
+
+// newDisplayMathParser returns a new BlockParser that parses $$...$$ blocks.
+func newDisplayMathParser() parser.BlockParser {
+	return &displayMathParser{}
+}
+
+func (b *displayMathParser) Trigger() []byte {
+	return []byte{'$'}
+}
+
+func (b *displayMathParser) Open(_ gast.Node, reader text.Reader, pc parser.Context) (gast.Node, parser.State) {
+	line, segment := reader.PeekLine()
+	pos := pc.BlockOffset()
+	if pos < 0 || !bytes.HasPrefix(line[pos:], []byte("$$")) {
+		return nil, parser.NoChildren
+	}
+
+	node := &DisplayMath{}
+	start := segment.Start + pos + 2
+	rest := line[pos+2:]
+
+	// The formula may end on the opening line: $$x^2$$
+	if end := bytes.Index(rest, []byte("$$")); end >= 0 {
+		if !util.IsBlank(rest[end+2:]) {
+			return nil, parser.NoChildren
+		}
+		node.Lines().Append(text.NewSegment(start, start+end))
+		node.closed = true
+	} else if !util.IsBlank(rest) {
+		node.Lines().Append(text.NewSegment(start, segment.Stop))
+	}
+
+	reader.Advance(segment.Len() - trailingNewline(line))
+	return node, parser.NoChildren
+}
+
+func (b *displayMathParser) Continue(node gast.Node, reader text.Reader, _ parser.Context) parser.State {
+	n := node.(*DisplayMath)
+	if n.closed {
+		return parser.Close
+	}
+
+	line, segment := reader.PeekLine()
+	if end := bytes.Index(line, []byte("$$")); end >= 0 {
+		n.Lines().Append(text.NewSegment(segment.Start, segment.Start+end))
+		reader.Advance(segment.Len() - trailingNewline(line))
+		return parser.Close
+	}
+
+	n.Lines().Append(segment)
+	reader.Advance(segment.Len() - trailingNewline(line))
+	return parser.Continue | parser.NoChildren
+}
+
+func (b *displayMathParser) Close(_ gast.Node, _ text.Reader, _ parser.Context) {
+	// nothing to do
+}
+
+func (b *displayMathParser) CanInterruptParagraph() bool {
+	return true
+}
+
+func (b *displayMathParser) CanAcceptIndentedLine() bool {
+	return false
+}
+
+// DisplayMath renderer
+func (r *UnicodeRenderer) renderDisplayMath(
+	w util.BufWriter,
+	source []byte,
+	node gast.Node,
+	entering bool,
+) (gast.WalkStatus, error) {
+	if !entering {
+		return gast.WalkContinue, nil
+	}
+
+	n := node.(*DisplayMath)
+	lines := layoutDisplayMath(n.Formula(source))
+
+	// Center the formula as a whole, keeping its lines aligned
+	width := 0
+	for _, line := range lines {
+		width = max(width, textWidth(line))
+	}
+	indent := strings.Repeat(" ", max((r.width-width)/2, 0))
+
+	for _, line := range lines {
+		if _, err := w.WriteString(indent + line + "\n"); err != nil {
+			return gast.WalkStop, err
+		}
+	}
+	if _, err := w.WriteString("\n"); err != nil {
+		return gast.WalkStop, err
+	}
+
+	return gast.WalkSkipChildren, nil
+}
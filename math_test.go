@@ -0,0 +1,44 @@
+package unidoc
+
+import "testing"
+
+func TestInlineMath(t *testing.T) {
+	tests := []struct {
+		input, want string
+	}{
+		{"Area $\\pi r^2$ here", "Area π r² here"},
+		{"Costs $5 and $10 today", "Costs $5 and $10 today"},
+		{"A $ x$ stays", "A $ x$ stays"},
+		{"Open $x", "Open $x"},
+		{"Escaped $a\\$b$ end", "Escaped a$b end"},
+	}
+	for _, tt := range tests {
+		if got := convertTest(t, tt.input); got != tt.want {
+			t.Errorf("Convert(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestDisplayMath(t *testing.T) {
+	tests := []struct {
+		name, input, want string
+	}{
+		{"block", "$$\n\\frac{a}{b}\n$$", `
+              a
+             ───
+              b`},
+		{"one line", "$$x^2$$", `
+              x²`},
+		{"unclosed", "$$\n\\frac{1}{2}", `
+              1
+             ───
+              2`},
+		{"text after", "$$\\alpha$$ text", `
+$α$ text`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkOutput(t, convertTest(t, tt.input), tt.want)
+		})
+	}
+}
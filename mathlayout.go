@@ -0,0 +1,340 @@
+package unidoc
+
+import (
+	"strings"
+)
+
+// mathBox is a rectangular block of text lines produced by the
+// two-dimensional math layout.
+type mathBox struct {
+	lines    []string
+	baseline int // Index of the line aligned with neighbouring boxes
+}
+
+// textMathBox returns a single line box.
+func textMathBox(s string) mathBox {
+	return mathBox{lines: []string{s}}
+}
+
+// width returns the width of the widest line of the box.
+func (b mathBox) width() int {
+	width := 0
+	for _, line := range b.lines {
+		width = max(width, textWidth(line))
+	}
+	return width
+}
+
+// height returns the number of lines of the box.
+func (b mathBox) height() int {
+	return len(b.lines)
+}
+
+// hconcat places boxes side by side, aligning their baselines.
+func hconcat(boxes ...mathBox) mathBox {
+	above, below := 0, 0
+	for _, box := range boxes {
+		above = max(above, box.baseline)
+		below = max(below, box.height()-box.baseline-1)
+	}
+
+	lines := make([]string, above+below+1)
+	for _, box := range boxes {
+		width := box.width()
+		offset := above - box.baseline
+		for i := range lines {
+			var line string
+			if j := i - offset; j >= 0 && j < box.height() {
+				line = box.lines[j]
+			}
+			lines[i] += padRight(line, width)
+		}
+	}
+	return mathBox{lines: lines, baseline: above}
+}
+
+// vstack stacks boxes on top of each other, centering them horizontally.
+func vstack(baseline int, boxes ...mathBox) mathBox {
+	width := 0
+	for _, box := range boxes {
+		width = max(width, box.width())
+	}
+
+	var lines []string
+	for _, box := range boxes {
+		for _, line := range box.lines {
+			lines = append(lines, centerText(line, width))
+		}
+	}
+	return mathBox{lines: lines, baseline: baseline}
+}
+
+// mapLines applies a text conversion to every line of a box.
+func (b mathBox) mapLines(convert func(string) string) mathBox {
+	lines := make([]string, len(b.lines))
+	for i, line := range b.lines {
+		lines[i] = convert(line)
+	}
+	return mathBox{lines: lines, baseline: b.baseline}
+}
+
+// layoutMath lays out a LaTeX expression over multiple lines.
+func layoutMath(expr mathExpr) mathBox {
+	if len(expr) == 0 {
+		return textMathBox("")
+	}
+	boxes := make([]mathBox, len(expr))
+	for i, atom := range expr {
+		boxes[i] = layoutAtom(atom)
+	}
+	return hconcat(boxes...)
+}
+
+// layoutAtom lays out a LaTeX atom and its scripts.
+func layoutAtom(a *mathAtom) mathBox {
+	var base mathBox
+	switch a.kind {
+	case mathText:
+		base = textMathBox(a.text)
+
+	case mathGroup:
+		base = layoutMath(a.args[0])
+
+	case mathFont:
+		base = layoutMath(a.args[0]).mapLines(mathFonts[a.text])
+
+	case mathFrac:
+		// Stack numerator and denominator around a fraction bar
+		num, den := layoutMath(a.args[0]), layoutMath(a.args[1])
+		bar := textMathBox(strings.Repeat("─", max(num.width(), den.width())+2))
+		base = vstack(num.height(), num, bar, den)
+
+	case mathSqrt:
+		base = layoutRoot(a.text, layoutMath(a.args[0]))
+
+	case mathOperator:
+		// Limits go above and below large operators
+		op := textMathBox(a.text)
+		if a.sup == nil && a.sub == nil {
+			return op
+		}
+		var sup, sub mathBox
+		if a.sup != nil {
+			sup = layoutMath(a.sup)
+		}
+		if a.sub != nil {
+			sub = layoutMath(a.sub)
+		}
+		boxes := []mathBox{op}
+		if a.sup != nil {
+			boxes = append([]mathBox{sup}, boxes...)
+		}
+		if a.sub != nil {
+			boxes = append(boxes, sub)
+		}
+		return vstack(len(sup.lines), boxes...)
+
+	case mathFenced:
+		inner := layoutMath(a.args[0])
+		base = hconcat(
+			delimiterBox(a.open, inner.height(), inner.baseline, true),
+			inner,
+			delimiterBox(a.close, inner.height(), inner.baseline, false),
+		)
+
+	case mathMatrix:
+		base = layoutMatrix(a)
+	}
+
+	return attachScripts(base, a.sup, a.sub)
+}
+
+// attachScripts attaches superscripts and subscripts to a box, using Unicode
+// script characters where possible and raising or lowering them otherwise.
+func attachScripts(base mathBox, sup, sub mathExpr) mathBox {
+	if sup == nil && sub == nil {
+		return base
+	}
+
+	var supBox, subBox mathBox
+	if sup != nil {
+		supBox = layoutMath(sup)
+	}
+	if sub != nil {
+		subBox = layoutMath(sub)
+	}
+
+	if base.height() == 1 && supBox.height() <= 1 && subBox.height() <= 1 {
+		supText, supOK := toSuperscriptText(stripSpaces(strings.Join(supBox.lines, "")))
+		subText, subOK := toSubscriptText(stripSpaces(strings.Join(subBox.lines, "")))
+		if supOK && subOK {
+			return textMathBox(base.lines[0] + subText + supText)
+		}
+	}
+
+	width := base.width()
+	indent := strings.Repeat(" ", width)
+
+	var lines []string
+	for _, line := range supBox.lines {
+		lines = append(lines, indent+line)
+	}
+	for _, line := range base.lines {
+		lines = append(lines, padRight(line, width))
+	}
+	for _, line := range subBox.lines {
+		lines = append(lines, indent+line)
+	}
+	return mathBox{lines: lines, baseline: supBox.height() + base.baseline}
+}
+
+// layoutRoot draws a radical sign with an overline around a radicand.
+func layoutRoot(index string, rad mathBox) mathBox {
+	overline := strings.Repeat("_", rad.width())
+
+	if rad.height() == 1 {
+		sign := rootSymbol(index)
+		return mathBox{
+			lines:    []string{strings.Repeat(" ", textWidth(sign)) + overline, sign + rad.lines[0]},
+			baseline: 1,
+		}
+	}
+
+	if index != "" {
+		if sup, ok := toSuperscriptText(index); ok {
+			index = sup
+		}
+	}
+	indent := strings.Repeat(" ", textWidth(index))
+
+	lines := []string{indent + "  " + overline}
+	for i, line := range rad.lines {
+		if i == rad.height()-1 {
+			lines = append(lines, index+"╲│"+line)
+		} else {
+			lines = append(lines, indent+" │"+line)
+		}
+	}
+	return mathBox{lines: lines, baseline: rad.baseline + 1}
+}
+
+// delimiterPieces holds the characters for drawing stretched delimiters:
+// top, extension, bottom and, for braces, the middle piece.
+var delimiterPieces = map[string][4]string{
+	"(": {"⎛", "⎜", "⎝", ""},
+	")": {"⎞", "⎟", "⎠", ""},
+	"[": {"⎡", "⎢", "⎣", ""},
+	"]": {"⎤", "⎥", "⎦", ""},
+	"{": {"⎧", "⎪", "⎩", "⎨"},
+	"}": {"⎫", "⎪", "⎭", "⎬"},
+	"⌈": {"⎡", "⎢", "⎢", ""},
+	"⌉": {"⎤", "⎥", "⎥", ""},
+	"⌊": {"⎢", "⎢", "⎣", ""},
+	"⌋": {"⎥", "⎥", "⎦", ""},
+	"|": {"│", "│", "│", ""},
+	"‖": {"║", "║", "║", ""},
+}
+
+// delimiterBox draws a delimiter stretched to the given height. Opening
+// delimiters are followed by a space and closing ones preceded by one.
+func delimiterBox(delim string, height, baseline int, opening bool) mathBox {
+	if delim == "" {
+		return mathBox{lines: make([]string, height), baseline: baseline}
+	}
+
+	lines := make([]string, height)
+	pieces, ok := delimiterPieces[delim]
+	for i := range lines {
+		switch {
+		case height == 1 || !ok:
+			// Delimiters without pieces are drawn once on the baseline
+			if i == baseline {
+				lines[i] = delim
+			}
+		case i == 0:
+			lines[i] = pieces[0]
+		case i == height-1:
+			lines[i] = pieces[2]
+		case pieces[3] != "" && i == height/2:
+			lines[i] = pieces[3]
+		default:
+			lines[i] = pieces[1]
+		}
+	}
+
+	box := mathBox{lines: lines, baseline: baseline}
+	if height == 1 {
+		return box
+	}
+	if opening {
+		return hconcat(box, textMathBox(" "))
+	}
+	return hconcat(textMathBox(" "), box)
+}
+
+// layoutMatrix lays out the cells of a matrix environment in aligned columns
+// between stretched delimiters.
+func layoutMatrix(a *mathAtom) mathBox {
+	cells := make([][]mathBox, len(a.rows))
+	var widths []int
+	for i, row := range a.rows {
+		cells[i] = make([]mathBox, len(row))
+		for j, cell := range row {
+			cells[i][j] = layoutMath(cell)
+			if j >= len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[j] = max(widths[j], cells[i][j].width())
+		}
+	}
+
+	// Matrices center their cells; alignment environments align them left
+	center := a.open != "" || a.text == "matrix"
+
+	var lines []string
+	for _, row := range cells {
+		var boxes []mathBox
+		for j, cell := range row {
+			if j > 0 {
+				boxes = append(boxes, textMathBox("  "))
+			}
+			if center {
+				boxes = append(boxes, cell.mapLines(func(s string) string {
+					return centerText(s, widths[j])
+				}))
+			} else {
+				boxes = append(boxes, cell.mapLines(func(s string) string {
+					return padRight(s, widths[j])
+				}))
+			}
+		}
+		if len(boxes) > 0 {
+			lines = append(lines, hconcat(boxes...).lines...)
+		}
+	}
+	if len(lines) == 0 {
+		lines = []string{""}
+	}
+
+	body := mathBox{lines: lines, baseline: (len(lines) - 1) / 2}
+	return hconcat(
+		delimiterBox(a.open, body.height(), body.baseline, true),
+		body,
+		delimiterBox(a.close, body.height(), body.baseline, false),
+	)
+}
+
+// layoutDisplayMath lays out a display formula, placing rows separated by \\
+// below each other.
+func layoutDisplayMath(src string) []string {
+	var lines []string
+	for _, row := range splitTopLevel(src, `\\`) {
+		if strings.TrimSpace(row) == "" {
+			continue
+		}
+		for _, line := range layoutMath(parseLatex(strings.TrimSpace(row))).lines {
+			lines = append(lines, strings.TrimRight(line, " "))
+		}
+	}
+	return lines
+}
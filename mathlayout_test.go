@@ -0,0 +1,51 @@
+package unidoc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLayoutDisplayMath(t *testing.T) {
+	tests := []struct {
+		name, src, want string
+	}{
+		{"fraction", `\frac{n(n+1)}{2}`, `
+ n(n+1)
+────────
+   2`},
+		{"linear", `x^2 + y^2 = z^2`, `
+x² + y² = z²`},
+		{"limits", `\sum_{i=1}^{n} i`, `
+ n
+ ∑  i
+i=1`},
+		{"root", `\sqrt{\frac{1}{x}}`, `
+  ___
+ │ 1
+ │───
+╲│ x`},
+		{"matrix", `\begin{pmatrix} a & b \\ c & d \end{pmatrix}`, `
+⎛ a  b ⎞
+⎝ c  d ⎠`},
+		{"delimiters", `\left(\frac{a}{b}\right)`, `
+⎛  a  ⎞
+⎜ ─── ⎟
+⎝  b  ⎠`},
+		{"rows", `a = b \\ \\ c = d`, `
+a = b
+c = d`},
+		{"script", `e^{i\pi}`, `
+ iπ
+e`},
+		{"unclosed", `\frac{a`, `
+ a
+───
+`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := strings.Join(layoutDisplayMath(tt.src), "\n")
+			checkOutput(t, got, tt.want)
+		})
+	}
+}
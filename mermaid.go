@@ -0,0 +1,69 @@
+package unidoc
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// mermaidProcessor renders ```mermaid blocks with diagrams in a subset of the
+// mermaid syntax, drawn with boxes and arrows. Flowcharts start with a graph
+// or flowchart line, sequence diagrams with sequenceDiagram, gantt charts
+// with gantt and timelines with timeline:
+//
+//	```mermaid
+//	graph LR
+//	  A[Request] --> B{Cached?}
+//	  B -->|yes| C(Response)
+//
+// The same processor renders ```graph and ```sequence blocks, where the first
+// line may be left out. Flowcharts from left to right that are wider than the
+// page are drawn top down. Other mermaid diagrams, diagrams that do not parse
+// and diagrams that do not fit are left to the boxed code rendering.
+type mermaidProcessor struct{}
+
+// ProcessBlock implements BlockProcessor.ProcessBlock.
+func (p mermaidProcessor) ProcessBlock(block FencedBlock, width int) ([]string, error) {
+	var kind string
+	for _, line := range strings.Split(block.Content, "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "%%") {
+			kind, _, _ = strings.Cut(strings.Fields(line)[0], ";")
+			break
+		}
+	}
+
+	switch {
+	case kind == "graph" || kind == "flowchart" || block.Language == "graph":
+		g, err := parseFlowchart(block.Content)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrUnprocessed, err)
+		}
+		lines, err := g.draw(width)
+		if errors.Is(err, ErrUnprocessed) && g.horizontal {
+			// Charts too wide from left to right may still fit top down
+			g.horizontal = false
+			return g.draw(width)
+		}
+		return lines, err
+	case kind == "sequenceDiagram" || block.Language == "sequence":
+		s, err := parseSequence(block.Content)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrUnprocessed, err)
+		}
+		return s.draw(width)
+	case kind == "gantt":
+		g, err := parseGantt(block.Content, false)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrUnprocessed, err)
+		}
+		return g.draw(width), nil
+	case kind == "timeline":
+		t, err := parseTimeline(block.Content)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrUnprocessed, err)
+		}
+		return t.draw(width), nil
+	default:
+		return nil, ErrUnprocessed
+	}
+}
@@ -0,0 +1,363 @@
+package unidoc
+
+import (
+	"bytes"
+	"encoding"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// optionText converts an option value from front matter or an attribute to
+// text.
+func optionText(value any) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	default:
+		return "", fmt.Errorf("unexpected value: %v", value)
+	}
+}
+
+// optionInt converts an option value to an integer.
+func optionInt(value any) (int, error) {
+	s, err := optionText(value)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(s)
+}
+
+// optionBool converts an option value to a boolean.
+func optionBool(value any) (bool, error) {
+	s, err := optionText(value)
+	if err != nil {
+		return false, err
+	}
+	return strconv.ParseBool(s)
+}
+
+// optionList converts an option value to a list of strings. Text values are
+// split at whitespace, so that bullets can be given as "★ ☆".
+func optionList(value any) ([]string, error) {
+	items, ok := value.([]any)
+	if !ok {
+		s, err := optionText(value)
+		return strings.Fields(s), err
+	}
+
+	list := make([]string, len(items))
+	for i, item := range items {
+		s, err := optionText(item)
+		if err != nil {
+			return nil, err
+		}
+		list[i] = s
+	}
+	return list, nil
+}
+
+// optionMap converts an option value to a map with text keys. YAML decodes
+// mappings with numeric keys, such as heading levels, with untyped keys.
+func optionMap(value any) (map[string]any, error) {
+	switch v := value.(type) {
+	case map[string]any:
+		return v, nil
+	case map[any]any:
+		m := make(map[string]any, len(v))
+		for key, item := range v {
+			m[fmt.Sprint(key)] = item
+		}
+		return m, nil
+	default:
+		return nil, fmt.Errorf("unexpected value: %v", value)
+	}
+}
+
+// setTextOption sets a style from an option value.
+func setTextOption(style encoding.TextUnmarshaler, value any) error {
+	s, err := optionText(value)
+	if err != nil {
+		return err
+	}
+	return style.UnmarshalText([]byte(s))
+}
+
+// setOption sets a rendering option by the name used in front matter and
+// attributes. It reports whether the name is a known option.
+func (c *Config) setOption(name string, value any) (bool, error) {
+	var err error
+	switch name {
+	case "italic-style":
+		err = setTextOption(&c.ItalicStyle, value)
+	case "strong-style":
+		err = setTextOption(&c.StrongStyle, value)
+	case "highlight-style":
+		err = setTextOption(&c.HighlightStyle, value)
+	case "underline-style":
+		err = setTextOption(&c.UnderlineStyle, value)
+	case "title-style":
+		err = setTextOption(&c.TitleStyle, value)
+	case "link-style":
+		err = setTextOption(&c.LinkStyle, value)
+
+	case "width":
+		c.Width, err = optionInt(value)
+	case "image-width":
+		c.ImageWidth, err = optionInt(value)
+	case "toc":
+		c.TOC, err = optionBool(value)
+	case "toc-depth":
+		c.TOCDepth, err = optionInt(value)
+	case "number-headings":
+		c.NumberHeadings, err = optionBool(value)
+	case "heading-numbers":
+		c.HeadingNumberFormats, err = optionList(value)
+
+	case "headings":
+		err = c.Headings.setOptionMap(value)
+	case "link-templates":
+		err = c.setLinkTemplates(value)
+
+	case "bullets":
+		var bullets []string
+		if bullets, err = optionList(value); err == nil && len(bullets) == 0 {
+			err = fmt.Errorf("no bullets given")
+		}
+		c.Bullets = bullets
+
+	case "numbers":
+		var names []string
+		names, err = optionList(value)
+		styles := make([]NumberStyle, len(names))
+		for i, name := range names {
+			if err == nil {
+				err = styles[i].UnmarshalText([]byte(name))
+			}
+		}
+		c.NumberStyles = styles
+
+	default:
+		return false, nil
+	}
+
+	if err != nil {
+		return true, fmt.Errorf("%s: %w", name, err)
+	}
+	return true, nil
+}
+
+// setLinkTemplates adds the URL templates from a front matter mapping.
+func (c *Config) setLinkTemplates(value any) error {
+	options, err := optionMap(value)
+	if err != nil {
+		return err
+	}
+
+	templates := cloneMap(c.LinkTemplates)
+	for name, option := range options {
+		if templates[name], err = optionText(option); err != nil {
+			return err
+		}
+	}
+	if err := checkLinkTemplates(templates); err != nil {
+		return err
+	}
+	c.LinkTemplates = templates
+	return nil
+}
+
+// applyOptions applies the rendering options from the unidoc key of front
+// matter. Unknown options, such as misspellings or options of newer
+// versions, are ignored like unknown attributes.
+func (c *Config) applyOptions(options map[string]any) error {
+	names := make([]string, 0, len(options))
+	for name := range options {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		if _, err := c.setOption(name, options[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// italicClass returns the italic style named by one of the classes of node.
+func italicClass(node gast.Node) (ItalicStyle, bool) {
+	class, ok := node.AttributeString("class")
+	if !ok {
+		return 0, false
+	}
+	value, _ := class.([]byte)
+	for _, name := range strings.Fields(string(value)) {
+		var style ItalicStyle
+		if style.UnmarshalText([]byte(name)) == nil {
+			return style, true
+		}
+	}
+	return 0, false
+}
+
+// applyAttributes applies the rendering options from the attributes of node.
+// A class naming an italic style selects it; other attributes unrelated to
+// rendering options, such as ids, are ignored, and so are options with
+// invalid values, so that a typo does not stop the whole document.
+func (c *Config) applyAttributes(node gast.Node) {
+	if style, ok := italicClass(node); ok {
+		c.ItalicStyle = style
+	}
+	for _, attr := range node.Attributes() {
+		config := *c
+		if _, err := config.setOption(string(attr.Name), attr.Value); err == nil {
+			*c = config
+		}
+	}
+}
+
+// overrideState holds the renderer state replaced by element attributes.
+type overrideState struct {
+	config Config
+	width  int
+}
+
+// withOverrides wraps a renderer function so that the rendering options from
+// the attributes of a node apply to the node and its children. A width can
+// only narrow the space available to the node.
+func (r *UnicodeRenderer) withOverrides(fn renderer.NodeRendererFunc) renderer.NodeRendererFunc {
+	return func(w util.BufWriter, source []byte, node gast.Node, entering bool) (gast.WalkStatus, error) {
+		if r.config.IgnoreOverrides || len(node.Attributes()) == 0 {
+			return fn(w, source, node, entering)
+		}
+
+		if !entering {
+			status, err := fn(w, source, node, entering)
+			saved := r.overrides[len(r.overrides)-1]
+			r.overrides = r.overrides[:len(r.overrides)-1]
+			r.config, r.width = saved.config, saved.width
+			return status, err
+		}
+
+		config := r.config
+		config.applyAttributes(node)
+		r.overrides = append(r.overrides, overrideState{config: r.config, width: r.width})
+		if config.Width != r.config.Width && config.Width > 0 {
+			r.width = max(min(config.Width, r.width), minWidth)
+		}
+		r.config = config
+
+		return fn(w, source, node, entering)
+	}
+}
+
+// attributeTransformer attaches attributes to blocks that goldmark does not
+// parse attributes for: fenced code blocks take them from the info string,
+// and any block takes them from a line of attributes directly above it:
+//
+//	{bullets="★ ☆"}
+//	- Item
+//
+// Paragraphs need a blank line after the attribute line, which would
+// otherwise be the first line of the paragraph. Lines with attributes that
+// mean nothing to the block below are left as text.
+type attributeTransformer struct{}
+
+// newAttributeTransformer returns a new ASTTransformer for block attributes.
+func newAttributeTransformer() parser.ASTTransformer {
+	return &attributeTransformer{}
+}
+
+// otherAttributes holds the attributes other than rendering options that an
+// attribute line may set on a block other than a fenced code block.
+var otherAttributes = []string{"id", "class", "annotate"}
+
+// knownAttributes reports whether attrs only hold attributes that apply to
+// node: rendering options, ids, classes and the annotations of tree lists.
+// Fenced code blocks take any attributes for their processors.
+func knownAttributes(attrs parser.Attributes, node gast.Node) bool {
+	if node.Kind() == gast.KindFencedCodeBlock {
+		return true
+	}
+	for _, attr := range attrs {
+		var config Config
+		if known, _ := config.setOption(string(attr.Name), attr.Value); !known &&
+			!slices.Contains(otherAttributes, string(attr.Name)) {
+			return false
+		}
+	}
+	return true
+}
+
+// parseAttributeLine parses text consisting of nothing but attributes.
+func parseAttributeLine(line []byte) (parser.Attributes, bool) {
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 || line[0] != '{' {
+		return nil, false
+	}
+	reader := text.NewReader(line)
+	attrs, ok := parser.ParseAttributes(reader)
+	if rest, _ := reader.PeekLine(); !ok || !util.IsBlank(rest) {
+		return nil, false
+	}
+	return attrs, true
+}
+
+func (t *attributeTransformer) Transform(doc *gast.Document, reader text.Reader, _ parser.Context) {
+	source := reader.Source()
+
+	var lines []*gast.Paragraph
+	_ = gast.Walk(doc, func(n gast.Node, entering bool) (gast.WalkStatus, error) {
+		if !entering {
+			return gast.WalkContinue, nil
+		}
+		switch n := n.(type) {
+		case *gast.FencedCodeBlock:
+			if n.Info == nil {
+				break
+			}
+			info := n.Info.Segment.Value(source)
+			if i := bytes.IndexByte(info, '{'); i >= 0 {
+				if attrs, ok := parseAttributeLine(info[i:]); ok {
+					for _, attr := range attrs {
+						n.SetAttribute(attr.Name, attr.Value)
+					}
+				}
+			}
+		case *gast.Paragraph:
+			if n.NextSibling() != nil && n.Lines().Len() == 1 {
+				segment := n.Lines().At(0)
+				if attrs, ok := parseAttributeLine(segment.Value(source)); ok && knownAttributes(attrs, n.NextSibling()) {
+					lines = append(lines, n)
+				}
+			}
+		}
+		return gast.WalkContinue, nil
+	})
+
+	for _, line := range lines {
+		segment := line.Lines().At(0)
+		attrs, _ := parseAttributeLine(segment.Value(source))
+		next := line.NextSibling()
+		for _, attr := range attrs {
+			next.SetAttribute(attr.Name, attr.Value)
+		}
+		line.Parent().RemoveChild(line.Parent(), line)
+	}
+}
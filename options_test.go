@@ -0,0 +1,154 @@
+package unidoc
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestOptionValues(t *testing.T) {
+	if s, err := optionText(1.5); s != "1.5" || err != nil {
+		t.Errorf("optionText(1.5) = %q, %v", s, err)
+	}
+	if _, err := optionText([]any{"a"}); err == nil {
+		t.Error("optionText of a list succeeded")
+	}
+	if n, err := optionInt("40"); n != 40 || err != nil {
+		t.Errorf("optionInt(40) = %d, %v", n, err)
+	}
+	if b, err := optionBool("yes"); err == nil {
+		t.Errorf("optionBool(yes) = %t, want an error", b)
+	}
+	for _, value := range []any{"★ ☆", []any{"★", "☆"}} {
+		if list, err := optionList(value); !slices.Equal(list, []string{"★", "☆"}) || err != nil {
+			t.Errorf("optionList(%q) = %q, %v", value, list, err)
+		}
+	}
+	m, err := optionMap(map[any]any{1: "a", "b": 2})
+	if want := map[string]any{"1": "a", "b": 2}; !maps.Equal(m, want) || err != nil {
+		t.Errorf("optionMap = %v, %v, want %v", m, err, want)
+	}
+}
+
+func TestFrontMatterOptions(t *testing.T) {
+	// Unknown options, and options that documents cannot set, are ignored
+	got := convertTest(t, `---
+unidoc:
+  bullets: "★ ☆"
+  italic-style: markers
+  colour: red
+  filters:
+    dot: [rm]
+---
+- *a*
+  - b`)
+	checkOutput(t, got, `
+★ *a*
+  ☆ b`)
+}
+
+func TestFrontMatterOptionsInvalid(t *testing.T) {
+	tests := []struct {
+		options, err string
+	}{
+		{"width: wide", "width:"},
+		{"bullets: []", "no bullets given"},
+		{"numbers: [decimal, bogus]", "invalid number style: bogus"},
+		{"italic-style: 3", "italic"},
+		{"toc: maybe", "toc:"},
+	}
+	for _, tt := range tests {
+		input := "---\nunidoc:\n  " + tt.options + "\n---\nText"
+		_, err := Convert([]byte(input), DefaultConfig())
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("Convert with %s: error %v, want %q", tt.options, err, tt.err)
+		}
+	}
+}
+
+func TestAttributes(t *testing.T) {
+	tests := []struct {
+		name, input, want string
+	}{
+		{"list", "{bullets=\"★\"}\n- a\n\n- b\n\nafter\n\n- c", `
+★ a
+
+★ b
+
+after
+
+• c`},
+		{"nested list", "- a\n\n  {bullets=\"★\"}\n  - b\n  - c", `
+• a
+  ★ b
+  ★ c`},
+		{"numbers", "{numbers=\"roman\"}\n1. a\n2. b", `
+ⅰ a
+ⅱ b`},
+		{"paragraph", "{italic-style=script}\n\n*x* and *y*\n\n*z*", `
+𝓍 and 𝓎
+
+𝘻`},
+		{"div width", "{width=20}\n::: note\naaa bbb ccc ddd eee fff ggg hhh\n:::", `
+╭──────────────────╮
+│ aaa bbb ccc ddd  │
+│ eee fff ggg hhh  │
+╰──────────────────╯`},
+		{"width only narrows", "{width=60}\n::: note\nText\n:::", `
+╭────────────────────────────╮
+│ Text                       │
+╰────────────────────────────╯`},
+		{"heading class", "# Title {.script}", `
+█ 𝒯𝒾𝓉𝓁ℯ
+═══════`},
+		{"not alone on a line", "{width=abc}\nx", `
+{width=abc}x`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkOutput(t, convertTest(t, tt.input), tt.want)
+		})
+	}
+}
+
+func TestAttributesInvalid(t *testing.T) {
+	// Invalid values are skipped and the rest of the document renders
+	got := convertTest(t, "{numbers=\"bogus\" bullets=\"★\"}\n1. a\n\n{width=abc}\n\nText\n\nEnd")
+	checkOutput(t, got, `
+① a
+
+Text
+
+End`)
+}
+
+func TestAttributeLineAsText(t *testing.T) {
+	got := convertTest(t, "{a=1}\n- b\n\n{#top}\n- c")
+	checkOutput(t, got, `
+{a=1}
+
+• b
+
+• c`)
+}
+
+func TestIgnoreOverrides(t *testing.T) {
+	got := convertTest(t, "---\nunidoc:\n  bullets: \"★\"\n---\n{bullets=\"☆\"}\n- a", func(c *Config) {
+		c.IgnoreOverrides = true
+	})
+	checkOutput(t, got, `
+• a`)
+}
+
+func TestOverridesKeepConfig(t *testing.T) {
+	config := DefaultConfig()
+	config.LinkTemplates = map[string]string{"issue": "https://a.example/{id}"}
+	input := "---\nunidoc:\n  link-templates:\n    issue: https://b.example/{id}\n---\n#1"
+	if _, err := Convert([]byte(input), config); err != nil {
+		t.Fatal(err)
+	}
+	if got := config.LinkTemplates["issue"]; got != "https://a.example/{id}" {
+		t.Errorf("link template of the caller changed to %q", got)
+	}
+}
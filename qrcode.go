@@ -0,0 +1,513 @@
+package unidoc
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// qrLevel is the error correction level of a QR code.
+type qrLevel int
+
+const (
+	qrLevelL qrLevel = iota // Recovers 7% of the data
+	qrLevelM                // Recovers 15% of the data
+	qrLevelQ                // Recovers 25% of the data
+	qrLevelH                // Recovers 30% of the data
+)
+
+// qrLevels maps the names of error correction levels to levels.
+var qrLevels = map[string]qrLevel{"L": qrLevelL, "M": qrLevelM, "Q": qrLevelQ, "H": qrLevelH}
+
+// qrFormatLevels holds the bits of each error correction level in the format
+// information.
+var qrFormatLevels = [4]int{1, 0, 3, 2}
+
+// qrBlockECC holds the error correction codewords per block for every level
+// and version, from ISO/IEC 18004 table 9.
+var qrBlockECC = [4][41]int{
+	{0, 7, 10, 15, 20, 26, 18, 20, 24, 30, 18, 20, 24, 26, 30, 22, 24, 28, 30, 28, 28, 28, 28, 30, 30, 26, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{0, 10, 16, 26, 18, 24, 16, 18, 22, 22, 26, 30, 22, 22, 24, 24, 28, 28, 26, 26, 26, 26, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28},
+	{0, 13, 22, 18, 26, 18, 24, 18, 22, 20, 24, 28, 26, 24, 20, 30, 24, 28, 28, 26, 30, 28, 30, 30, 30, 30, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{0, 17, 28, 22, 16, 22, 28, 26, 26, 24, 28, 24, 28, 22, 24, 24, 30, 28, 28, 26, 28, 30, 24, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+}
+
+// qrBlocks holds the number of error correction blocks for every level and
+// version, from ISO/IEC 18004 table 9.
+var qrBlocks = [4][41]int{
+	{0, 1, 1, 1, 1, 1, 2, 2, 2, 2, 4, 4, 4, 4, 4, 6, 6, 6, 6, 7, 8, 8, 9, 9, 10, 12, 12, 12, 13, 14, 15, 16, 17, 18, 19, 19, 20, 21, 22, 24, 25},
+	{0, 1, 1, 1, 2, 2, 4, 4, 4, 5, 5, 5, 8, 9, 9, 10, 10, 11, 13, 14, 16, 17, 17, 18, 20, 21, 23, 25, 26, 28, 29, 31, 33, 35, 37, 38, 40, 43, 45, 47, 49},
+	{0, 1, 1, 2, 2, 4, 4, 6, 6, 8, 8, 8, 10, 12, 16, 12, 17, 16, 18, 21, 20, 23, 23, 25, 27, 29, 34, 34, 35, 38, 40, 43, 45, 48, 51, 53, 56, 59, 62, 65, 68},
+	{0, 1, 1, 2, 4, 4, 4, 5, 6, 8, 8, 11, 11, 16, 16, 18, 16, 19, 21, 25, 25, 25, 34, 30, 32, 35, 37, 40, 42, 45, 48, 51, 54, 57, 60, 63, 66, 70, 74, 77, 81},
+}
+
+// errQRTooLong is returned for data that does not fit into the largest QR
+// code.
+var errQRTooLong = errors.New("text too long for a QR code")
+
+// qrRawModules returns the number of modules of a version that hold data and
+// error correction codewords.
+func qrRawModules(version int) int {
+	n := (16*version+128)*version + 64
+	if version >= 2 {
+		align := version/7 + 2
+		n -= (25*align-10)*align - 55
+		if version >= 7 {
+			n -= 36
+		}
+	}
+	return n
+}
+
+// qrDataCodewords returns the number of data codewords of a version and level.
+func qrDataCodewords(version int, level qrLevel) int {
+	return qrRawModules(version)/8 - qrBlockECC[level][version]*qrBlocks[level][version]
+}
+
+// gfMultiply multiplies two elements of the Galois field GF(2⁸) of QR codes.
+func gfMultiply(x, y byte) byte {
+	var z int
+	for i := 7; i >= 0; i-- {
+		z = z<<1 ^ (z>>7)*0x11D
+		z ^= int(y>>i&1) * int(x)
+	}
+	return byte(z)
+}
+
+// rsDivisor returns the generator polynomial of a Reed-Solomon code with
+// degree error correction codewords, without its leading coefficient.
+func rsDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+	root := byte(1)
+	for range degree {
+		for j := range result {
+			result[j] = gfMultiply(result[j], root)
+			if j+1 < len(result) {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = gfMultiply(root, 2)
+	}
+	return result
+}
+
+// rsRemainder returns the error correction codewords of data.
+func rsRemainder(data, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i, coefficient := range divisor {
+			result[i] ^= gfMultiply(coefficient, factor)
+		}
+	}
+	return result
+}
+
+// A qrCode is a QR code being built, with the modules of the function
+// patterns marked.
+type qrCode struct {
+	size     int
+	dark     [][]bool
+	function [][]bool
+}
+
+// setFunction sets a module of a function pattern.
+func (q *qrCode) setFunction(x, y int, dark bool) {
+	q.dark[y][x] = dark
+	q.function[y][x] = true
+}
+
+// drawFinder draws a finder pattern with its separator around a center.
+func (q *qrCode) drawFinder(cx, cy int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			x, y := cx+dx, cy+dy
+			if x >= 0 && y >= 0 && x < q.size && y < q.size {
+				distance := max(abs(dx), abs(dy))
+				q.setFunction(x, y, distance != 2 && distance != 4)
+			}
+		}
+	}
+}
+
+// drawAlignment draws an alignment pattern around a center.
+func (q *qrCode) drawAlignment(cx, cy int) {
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -2; dx <= 2; dx++ {
+			q.setFunction(cx+dx, cy+dy, max(abs(dx), abs(dy)) != 1)
+		}
+	}
+}
+
+// drawFormat draws the format information of a level and mask, twice.
+func (q *qrCode) drawFormat(level qrLevel, mask int) {
+	data := qrFormatLevels[level]<<3 | mask
+	rem := data
+	for range 10 {
+		rem = rem<<1 ^ (rem>>9)*0x537
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+	bit := func(i int) bool { return bits>>i&1 != 0 }
+
+	for i := range 6 {
+		q.setFunction(8, i, bit(i))
+	}
+	q.setFunction(8, 7, bit(6))
+	q.setFunction(8, 8, bit(7))
+	q.setFunction(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		q.setFunction(14-i, 8, bit(i))
+	}
+	for i := range 8 {
+		q.setFunction(q.size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		q.setFunction(8, q.size-15+i, bit(i))
+	}
+	q.setFunction(8, q.size-8, true) // Dark module
+}
+
+// drawVersion draws the version information of versions 7 and up, twice.
+func (q *qrCode) drawVersion(version int) {
+	if version < 7 {
+		return
+	}
+	rem := version
+	for range 12 {
+		rem = rem<<1 ^ (rem>>11)*0x1F25
+	}
+	bits := version<<12 | rem
+	for i := range 18 {
+		dark := bits>>i&1 != 0
+		a, b := q.size-11+i%3, i/3
+		q.setFunction(a, b, dark)
+		q.setFunction(b, a, dark)
+	}
+}
+
+// alignmentPositions returns the centers of the alignment patterns of a
+// version along each axis.
+func alignmentPositions(version int) []int {
+	if version == 1 {
+		return nil
+	}
+	count := version/7 + 2
+	step := (version*8 + count*3 + 5) / (count*4 - 4) * 2
+	positions := make([]int, count)
+	positions[0] = 6
+	for i, pos := count-1, version*4+10; i > 0; i, pos = i-1, pos-step {
+		positions[i] = pos
+	}
+	return positions
+}
+
+// qrMasked reports whether a mask pattern inverts a module.
+func qrMasked(mask, x, y int) bool {
+	switch mask {
+	case 0:
+		return (x+y)%2 == 0
+	case 1:
+		return y%2 == 0
+	case 2:
+		return x%3 == 0
+	case 3:
+		return (x+y)%3 == 0
+	case 4:
+		return (x/3+y/2)%2 == 0
+	case 5:
+		return x*y%2+x*y%3 == 0
+	case 6:
+		return (x*y%2+x*y%3)%2 == 0
+	default:
+		return ((x+y)%2+x*y%3)%2 == 0
+	}
+}
+
+// applyMask inverts the data modules of a mask pattern. Applying it twice
+// undoes it.
+func (q *qrCode) applyMask(mask int) {
+	for y := range q.size {
+		for x := range q.size {
+			if !q.function[y][x] && qrMasked(mask, x, y) {
+				q.dark[y][x] = !q.dark[y][x]
+			}
+		}
+	}
+}
+
+// penalty scores the modules by the rules of ISO/IEC 18004 for choosing a
+// mask: long runs, 2×2 blocks, patterns like finders and an unbalanced
+// share of dark modules all count against it.
+func (q *qrCode) penalty() int {
+	finderLike := [2][11]bool{
+		{true, false, true, true, true, false, true, false, false, false, false},
+		{false, false, false, false, true, false, true, true, true, false, true},
+	}
+	score, darkCount := 0, 0
+	for _, vertical := range []bool{false, true} {
+		at := func(i, j int) bool {
+			if vertical {
+				return q.dark[j][i]
+			}
+			return q.dark[i][j]
+		}
+		for i := range q.size {
+			run := 0
+			for j := range q.size {
+				if j > 0 && at(i, j) == at(i, j-1) {
+					run++
+				} else {
+					run = 1
+				}
+				if run == 5 {
+					score += 3
+				} else if run > 5 {
+					score++
+				}
+				for _, pattern := range finderLike {
+					if j+len(pattern) > q.size {
+						continue
+					}
+					match := true
+					for k, dark := range pattern {
+						if at(i, j+k) != dark {
+							match = false
+							break
+						}
+					}
+					if match {
+						score += 40
+					}
+				}
+			}
+		}
+	}
+	for y := range q.size {
+		for x := range q.size {
+			if q.dark[y][x] {
+				darkCount++
+			}
+			if x > 0 && y > 0 {
+				dark := q.dark[y][x]
+				if q.dark[y-1][x] == dark && q.dark[y][x-1] == dark && q.dark[y-1][x-1] == dark {
+					score += 3
+				}
+			}
+		}
+	}
+	total := q.size * q.size
+	score += ((abs(darkCount*20-total*10)+total-1)/total - 1) * 10
+	return score
+}
+
+// encodeQR encodes data in byte mode into the smallest QR code with the error
+// correction level and returns its modules, true for dark.
+func encodeQR(data []byte, level qrLevel) ([][]bool, error) {
+	version := 1
+	for ; ; version++ {
+		if version > 40 {
+			return nil, errQRTooLong
+		}
+		countBits := 8
+		if version >= 10 {
+			countBits = 16
+		}
+		if 4+countBits+8*len(data) <= qrDataCodewords(version, level)*8 {
+			break
+		}
+	}
+
+	// Mode indicator, character count, data, terminator and padding
+	var bits []bool
+	appendBits := func(value, length int) {
+		for i := length - 1; i >= 0; i-- {
+			bits = append(bits, value>>i&1 != 0)
+		}
+	}
+	appendBits(0b0100, 4)
+	if version >= 10 {
+		appendBits(len(data), 16)
+	} else {
+		appendBits(len(data), 8)
+	}
+	for _, b := range data {
+		appendBits(int(b), 8)
+	}
+	capacity := qrDataCodewords(version, level) * 8
+	appendBits(0, min(4, capacity-len(bits)))
+	appendBits(0, (8-len(bits)%8)%8)
+	for pad := 0xEC; len(bits) < capacity; pad ^= 0xEC ^ 0x11 {
+		appendBits(pad, 8)
+	}
+	codewords := make([]byte, len(bits)/8)
+	for i, bit := range bits {
+		if bit {
+			codewords[i/8] |= 1 << (7 - i%8)
+		}
+	}
+
+	// Split into blocks, add error correction and interleave
+	blockCount, ecc := qrBlocks[level][version], qrBlockECC[level][version]
+	raw := qrRawModules(version) / 8
+	shortBlocks, shortLength := blockCount-raw%blockCount, raw/blockCount
+	divisor := rsDivisor(ecc)
+	var blocks [][]byte
+	for i, k := 0, 0; i < blockCount; i++ {
+		length := shortLength - ecc
+		if i >= shortBlocks {
+			length++
+		}
+		block := append([]byte(nil), codewords[k:k+length]...)
+		k += length
+		if i < shortBlocks {
+			block = append(block, 0) // Room for the longer blocks
+		}
+		blocks = append(blocks, append(block, rsRemainder(codewords[k-length:k], divisor)...))
+	}
+	var stream []byte
+	for i := range blocks[0] {
+		for j, block := range blocks {
+			if i != shortLength-ecc || j >= shortBlocks {
+				stream = append(stream, block[i])
+			}
+		}
+	}
+
+	// Function patterns
+	size := version*4 + 17
+	q := &qrCode{size: size, dark: make([][]bool, size), function: make([][]bool, size)}
+	for y := range size {
+		q.dark[y], q.function[y] = make([]bool, size), make([]bool, size)
+	}
+	for i := range size {
+		q.setFunction(6, i, i%2 == 0)
+		q.setFunction(i, 6, i%2 == 0)
+	}
+	q.drawFinder(3, 3)
+	q.drawFinder(size-4, 3)
+	q.drawFinder(3, size-4)
+	positions := alignmentPositions(version)
+	for i, x := range positions {
+		for j, y := range positions {
+			last := len(positions) - 1
+			if i == 0 && j == 0 || i == 0 && j == last || i == last && j == 0 {
+				continue // Finder patterns
+			}
+			q.drawAlignment(x, y)
+		}
+	}
+	q.drawFormat(level, 0) // Reserves the modules until the mask is chosen
+	q.drawVersion(version)
+
+	// Codewords zigzag upwards and downwards in columns of two
+	i := 0
+	for right := size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5 // Skip the vertical timing pattern
+		}
+		for vertical := range size {
+			for j := range 2 {
+				x, y := right-j, vertical
+				if (right+1)&2 == 0 {
+					y = size - 1 - vertical
+				}
+				if !q.function[y][x] && i < len(stream)*8 {
+					q.dark[y][x] = stream[i/8]>>(7-i%8)&1 != 0
+					i++
+				}
+			}
+		}
+	}
+
+	best, lowest := 0, -1
+	for mask := range 8 {
+		q.applyMask(mask)
+		q.drawFormat(level, mask)
+		if score := q.penalty(); lowest < 0 || score < lowest {
+			best, lowest = mask, score
+		}
+		q.applyMask(mask)
+	}
+	q.applyMask(best)
+	q.drawFormat(level, best)
+	return q.dark, nil
+}
+
+// drawQR draws the modules of a QR code with half blocks, two rows of modules
+// to a line, inside a quiet zone of light modules. Dark modules are drawn
+// with blocks, or light ones if invert is set, for dark backgrounds.
+func drawQR(modules [][]bool, quiet int, invert bool) []string {
+	size := len(modules) + 2*quiet
+	dark := func(x, y int) bool {
+		x, y = x-quiet, y-quiet
+		inside := x >= 0 && y >= 0 && x < len(modules) && y < len(modules)
+		return inside && modules[y][x] != invert || !inside && invert
+	}
+
+	var lines []string
+	for y := 0; y < size; y += 2 {
+		var b strings.Builder
+		for x := range size {
+			top, bottom := dark(x, y), y+1 < size && dark(x, y+1)
+			switch {
+			case top && bottom:
+				b.WriteRune('█')
+			case top:
+				b.WriteRune('▀')
+			case bottom:
+				b.WriteRune('▄')
+			default:
+				b.WriteByte(' ')
+			}
+		}
+		lines = append(lines, strings.TrimRight(b.String(), " "))
+	}
+	return lines
+}
+
+// qrProcessor renders ```qr blocks as QR codes of their text. The attributes
+// level sets the error correction level from L to H, M by default, quiet the
+// width of the quiet zone, 4 modules by default, and invert=true swaps dark
+// and light for terminals with a dark background:
+//
+//	```qr {level=Q}
+//	https://github.com/0x5a17ed/unidoc
+type qrProcessor struct{}
+
+// ProcessBlock implements BlockProcessor.ProcessBlock.
+func (p qrProcessor) ProcessBlock(block FencedBlock, width int) ([]string, error) {
+	level := qrLevelM
+	if text, ok := block.Attributes["level"]; ok {
+		if level, ok = qrLevels[strings.ToUpper(text)]; !ok {
+			return nil, fmt.Errorf("%w: invalid level: %s", ErrUnprocessed, text)
+		}
+	}
+	quiet, invert := 4, false
+	if text, ok := block.Attributes["quiet"]; ok {
+		n, err := strconv.Atoi(text)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("%w: invalid quiet: %s", ErrUnprocessed, text)
+		}
+		quiet = n
+	}
+	if text, ok := block.Attributes["invert"]; ok {
+		value, err := strconv.ParseBool(text)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid invert: %s", ErrUnprocessed, text)
+		}
+		invert = value
+	}
+
+	modules, err := encodeQR([]byte(strings.TrimRight(block.Content, "\n")), level)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnprocessed, err)
+	}
+	if len(modules)+2*quiet > width {
+		return nil, ErrUnprocessed
+	}
+	return drawQR(modules, quiet, invert), nil
+}
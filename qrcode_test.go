@@ -0,0 +1,146 @@
+package unidoc
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRSRemainder(t *testing.T) {
+	if got, want := rsDivisor(7), []byte{127, 122, 154, 164, 11, 68, 117}; !slices.Equal(got, want) {
+		t.Errorf("rsDivisor(7) = %v, want %v", got, want)
+	}
+	// Version 1-M code of HELLO WORLD
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	want := []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}
+	if got := rsRemainder(data, rsDivisor(10)); !slices.Equal(got, want) {
+		t.Errorf("rsRemainder = %v, want %v", got, want)
+	}
+}
+
+// qrFormatStrings holds the format information of levels L and M for masks 0
+// to 7, from ISO/IEC 18004 annex C.
+var qrFormatStrings = map[qrLevel][]string{
+	qrLevelL: {
+		"111011111000100", "111001011110011", "111110110101010", "111100010011101",
+		"110011000101111", "110001100011000", "110110001000001", "110100101110110",
+	},
+	qrLevelM: {
+		"101010000010010", "101000100100101", "101111001111100", "101101101001011",
+		"100010111111001", "100000011001110", "100111110010111", "100101010100000",
+	},
+}
+
+func TestEncodeQRFormat(t *testing.T) {
+	for level, formats := range qrFormatStrings {
+		modules, err := encodeQR([]byte("https://github.com/0x5a17ed/unidoc"), level)
+		if err != nil {
+			t.Fatal(err)
+		}
+		size := len(modules)
+		bit := func(dark bool) string {
+			if dark {
+				return "1"
+			}
+			return "0"
+		}
+
+		// Both copies of the format information, from bit 14 down to bit 0
+		var first, second string
+		for i := 14; i >= 0; i-- {
+			switch {
+			case i < 6:
+				first += bit(modules[i][8])
+			case i == 6:
+				first += bit(modules[7][8])
+			case i == 7:
+				first += bit(modules[8][8])
+			case i == 8:
+				first += bit(modules[8][7])
+			default:
+				first += bit(modules[8][14-i])
+			}
+			if i < 8 {
+				second += bit(modules[8][size-1-i])
+			} else {
+				second += bit(modules[size-15+i][8])
+			}
+		}
+		if !slices.Contains(formats, first) {
+			t.Errorf("level %d: format %s is not one of %v", level, first, formats)
+		}
+		if second != first {
+			t.Errorf("level %d: second format copy %s, want %s", level, second, first)
+		}
+		if !modules[size-8][8] {
+			t.Errorf("level %d: dark module missing", level)
+		}
+	}
+}
+
+func TestEncodeQRVersion(t *testing.T) {
+	tests := []struct {
+		length, size int
+	}{
+		{17, 21}, // Fills the 19 data codewords of version 1-L
+		{18, 25},
+		{2953, 177}, // Largest code
+	}
+	for _, tt := range tests {
+		modules, err := encodeQR([]byte(strings.Repeat("x", tt.length)), qrLevelL)
+		if err != nil {
+			t.Fatalf("%d bytes: %v", tt.length, err)
+		}
+		if len(modules) != tt.size {
+			t.Errorf("%d bytes: size %d, want %d", tt.length, len(modules), tt.size)
+		}
+	}
+	if _, err := encodeQR([]byte(strings.Repeat("x", 2954)), qrLevelL); !errors.Is(err, errQRTooLong) {
+		t.Errorf("got error %v, want errQRTooLong", err)
+	}
+}
+
+func TestDrawQR(t *testing.T) {
+	modules := [][]bool{{true, false}, {false, true}}
+	if got, want := drawQR(modules, 1, false), []string{" ▄", "  ▀"}; !slices.Equal(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got, want := drawQR(modules, 1, true), []string{"█▀██", "██▄█"}; !slices.Equal(got, want) {
+		t.Errorf("inverted: got %q, want %q", got, want)
+	}
+}
+
+func TestQR(t *testing.T) {
+	got, err := processTest(t, FencedBlock{Language: "qr", Content: "hi\n", Attributes: map[string]string{"quiet": "1"}}, 30)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkOutput(t, got, `
+ ▄▄▄▄▄▄▄  ▄▄▄▄ ▄▄▄▄▄▄▄
+ █ ▄▄▄ █ ▄█▀▄█ █ ▄▄▄ █
+ █ ███ █ █▀ ▄█ █ ███ █
+ █▄▄▄▄▄█ █▀▄ █ █▄▄▄▄▄█
+ ▄ ▄▄▄▄▄ ▀ ▀▀█ ▄▄▄▄▄
+  ▀██ ▀▄█ ▄▀▄▀▄ ▀▄▄▄▄▀
+ ███ █ ▄  ▀ ▄ ▄ ▀█ █ ▄
+ ▄▄▄▄▄▄▄ ▀ ▄▀█▀█▄ ▀ ▄▀
+ █ ▄▄▄ █ █▀▀▀█▀▀▄ ▀▄ ▀
+ █ ███ █ █▀▀▄▀▄ ▀▄▄█
+ █▄▄▄▄▄█ ▄▀█▄ ▄ ▀█▄█▄
+`)
+}
+
+func TestQRLinkStyle(t *testing.T) {
+	input := "[Site](https://example.com)"
+	for _, width := range []int{33, 32} {
+		got := convertTest(t, input, func(c *Config) {
+			c.Width = width
+			c.LinkStyle = LinkStyleQR
+		})
+		hasCode := strings.Contains(got, "█")
+		if want := width == 33; hasCode != want || !strings.Contains(got, "https://example.com") {
+			t.Errorf("width %d: got:\n%s", width, got)
+		}
+	}
+}
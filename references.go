@@ -0,0 +1,235 @@
+package unidoc
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// ReferenceType is the type of a reference.
+type ReferenceType int
+
+const (
+	ReferenceWiki      ReferenceType = iota // Wiki link: [[Page Name]]
+	ReferenceIssue                          // Issue reference: #123
+	ReferenceRepoIssue                      // Cross-repository issue reference: org/repo#45
+	ReferenceMention                        // User mention: @user
+)
+
+// referenceTemplateNames holds the names of the URL templates of the
+// reference types, as used in Config.LinkTemplates.
+var referenceTemplateNames = []string{"wiki", "issue", "repo-issue", "mention"}
+
+// checkLinkTemplates checks that all templates belong to a reference type.
+func checkLinkTemplates(templates map[string]string) error {
+	for name := range templates {
+		if !slices.Contains(referenceTemplateNames, name) {
+			return fmt.Errorf("unknown link template: %s", name)
+		}
+	}
+	return nil
+}
+
+// A Reference represents a wiki link, an issue reference or a mention.
+type Reference struct {
+	gast.BaseInline
+	RefType ReferenceType
+	Target  string // Page name, issue number or user name
+	Repo    string // Repository of a cross-repository issue reference
+	Label   string // Text of a wiki link: [[Target|Label]]
+}
+
+// KindReference is a NodeKind of the Reference node.
+var KindReference = gast.NewNodeKind("Reference")
+
+// Kind implements Node.Kind.
+func (n *Reference) Kind() gast.NodeKind {
+	return KindReference
+}
+
+// Dump implements Node.Dump.
+func (n *Reference) Dump(source []byte, level int) {
+	gast.DumpHelper(n, source, level, map[string]string{
+		"Type":   referenceTemplateNames[n.RefType],
+		"Target": n.Target,
+		"Repo":   n.Repo,
+	}, nil)
+}
+
+// Plain returns the reference as written, without wiki link brackets.
+func (n *Reference) Plain() string {
+	switch n.RefType {
+	case ReferenceWiki:
+		return n.Label
+	case ReferenceIssue:
+		return "#" + n.Target
+	case ReferenceRepoIssue:
+		return n.Repo + "#" + n.Target
+	default:
+		return "@" + n.Target
+	}
+}
+
+// URL expands the template for the reference type from templates, where
+// {id} stands for the page name, issue number or user name and {repo} for
+// the repository. It returns "" if there is no template for the type.
+func (n *Reference) URL(templates map[string]string) string {
+	template := templates[referenceTemplateNames[n.RefType]]
+	if template == "" {
+		return ""
+	}
+	return strings.NewReplacer("{id}", url.PathEscape(n.Target), "{repo}", n.Repo).Replace(template)
+}
+
+var (
+	// issueNumber matches the number of an issue reference after the '#'.
+	issueNumber = regexp.MustCompile(`^#([0-9]+)`)
+	// userName matches the user name of a mention after the '@'.
+	userName = regexp.MustCompile(`^@([\pL\pN](?:[\pL\pN_.-]*[\pL\pN])?)`)
+	// repoName matches the repository at the end of the text before the '#'
+	// of a cross-repository issue reference.
+	repoName = regexp.MustCompile(`(?:^|[\s([{"'*_~])([A-Za-z0-9][\w.-]*/[\w.-]+)$`)
+)
+
+// isReferenceBoundary reports whether a reference may follow a character.
+// Characters within words and URLs, such as the one in "C#" or "a@b.com",
+// keep text from turning into references.
+func isReferenceBoundary(r rune) bool {
+	return unicode.IsSpace(r) || strings.ContainsRune(`([{"'*_~`, r)
+}
+
+// isWordByte reports whether a byte continues a word, so that "#12a" is no
+// issue reference.
+func isWordByte(b byte) bool {
+	return b == '_' || b < utf8.RuneSelf && (unicode.IsLetter(rune(b)) || unicode.IsDigit(rune(b)))
+}
+
+type referenceParser struct{}
+
+// newReferenceParser returns a new InlineParser that parses [[wiki links]],
+// #123 and org/repo#45 issue references and @user mentions.
+func newReferenceParser() parser.InlineParser {
+	return &referenceParser{}
+}
+
+func (s *referenceParser) Trigger() []byte {
+	return []byte{'[', '#', '@'}
+}
+
+func (s *referenceParser) Parse(parent gast.Node, block text.Reader, pc parser.Context) gast.Node {
+	line, segment := block.PeekLine()
+	if pc.IsInLinkLabel() {
+		return nil
+	}
+
+	switch line[0] {
+	case '[':
+		return parseWikiLink(line, block)
+
+	case '@':
+		match := userName.FindSubmatch(line)
+		if match == nil || !isReferenceBoundary(block.PrecendingCharacter()) {
+			return nil
+		}
+		block.Advance(len(match[0]))
+		return &Reference{RefType: ReferenceMention, Target: string(match[1])}
+
+	default:
+		match := issueNumber.FindSubmatch(line)
+		if match == nil || len(match[0]) < len(line) && isWordByte(line[len(match[0])]) {
+			return nil
+		}
+		ref := &Reference{RefType: ReferenceIssue, Target: string(match[1])}
+		if !isReferenceBoundary(block.PrecendingCharacter()) {
+			// The repository of a cross-repository reference has already
+			// been added as text, from which it is taken back
+			last, ok := parent.LastChild().(*gast.Text)
+			if !ok || last.Segment.Stop != segment.Start {
+				return nil
+			}
+			repo := repoName.FindSubmatchIndex(last.Segment.Value(block.Source()))
+			if repo == nil {
+				return nil
+			}
+			ref.RefType = ReferenceRepoIssue
+			ref.Repo = string(last.Segment.Value(block.Source())[repo[2]:repo[3]])
+			last.Segment = last.Segment.WithStop(last.Segment.Start + repo[2])
+			if last.Segment.IsEmpty() {
+				parent.RemoveChild(parent, last)
+			}
+		}
+		block.Advance(len(match[0]))
+		return ref
+	}
+}
+
+// parseWikiLink parses a [[Page Name]] or [[Page Name|Label]] wiki link.
+func parseWikiLink(line []byte, block text.Reader) gast.Node {
+	content, ok := bytes.CutPrefix(line, []byte("[["))
+	if !ok {
+		return nil
+	}
+	end := bytes.Index(content, []byte("]]"))
+	if end < 0 || bytes.ContainsAny(content[:end], "[\n") {
+		return nil
+	}
+
+	target, label, found := strings.Cut(string(content[:end]), "|")
+	target, label = strings.TrimSpace(target), strings.TrimSpace(label)
+	if !found || label == "" {
+		label = target
+	}
+	if target == "" {
+		return nil
+	}
+	block.Advance(end + 4)
+	return &Reference{RefType: ReferenceWiki, Target: target, Label: label}
+}
+
+func (s *referenceParser) CloseBlock(_ gast.Node, _ parser.Context) {
+	// nothing to do
+}
+
+// Reference renderer
+func (r *UnicodeRenderer) renderReference(
+	w util.BufWriter,
+	_ []byte,
+	node gast.Node,
+	entering bool,
+) (gast.WalkStatus, error) {
+	if !entering {
+		return gast.WalkContinue, nil
+	}
+
+	n := node.(*Reference)
+	var text string
+	switch n.RefType {
+	case ReferenceWiki:
+		text = "⟪" + r.toSmartDashes(n.Label) + "⟫"
+	case ReferenceIssue:
+		text = "#" + toBoldSansSerifText(n.Target)
+	case ReferenceRepoIssue:
+		text = n.Repo + "#" + toBoldSansSerifText(n.Target)
+	case ReferenceMention:
+		text = "@" + toBoldSansSerifText(n.Target)
+	}
+
+	// References without a URL template stay plain text
+	if url := n.URL(r.config.LinkTemplates); url != "" {
+		text = r.linkPrefix() + text + r.linkSuffix(url)
+	}
+	if _, err := w.WriteString(text); err != nil {
+		return gast.WalkStop, err
+	}
+	return gast.WalkSkipChildren, nil
+}
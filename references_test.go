@@ -0,0 +1,74 @@
+package unidoc
+
+import (
+	"strings"
+	"testing"
+)
+
+const referenceText = "See [[Home]], [[Setup Guide|setup]], #12, org/repo#7, @alice, a#1, mail@x.org, `#3`, [#4](https://x) and #abc."
+
+var referenceTemplates = map[string]string{
+	"wiki":       "https://w.example/{id}",
+	"issue":      "https://i.example/{id}",
+	"repo-issue": "https://g.example/{repo}/issues/{id}",
+	"mention":    "https://g.example/{id}",
+}
+
+func TestReferences(t *testing.T) {
+	tests := []struct {
+		name   string
+		config func(*Config)
+		want   string
+	}{
+		{"no templates", func(*Config) {}, `
+See ⟪Home⟫, ⟪setup⟫, #𝟭𝟮, org/repo#𝟳, @𝗮𝗹𝗶𝗰𝗲, a#1, mail@x.org, ⌜#3⌝, [#4] 🔗 <https://x> and #abc.`},
+		{"templates", func(c *Config) { c.LinkTemplates = referenceTemplates }, `
+See [⟪Home⟫] 🔗 <https://w.example/Home>, [⟪setup⟫] 🔗 <https://w.example/Setup%20Guide>, [#𝟭𝟮] 🔗 <https://i.example/12>, [org/repo#𝟳] 🔗 <https://g.example/org/repo/issues/7>, [@𝗮𝗹𝗶𝗰𝗲] 🔗 <https://g.example/alice>, a#1, mail@x.org, ⌜#3⌝, [#4] 🔗 <https://x> and #abc.`},
+		{"footnotes", func(c *Config) {
+			c.LinkStyle = LinkStyleFootnote
+			c.LinkTemplates = map[string]string{"issue": "https://i.example/{id}", "wiki": "https://w.example/{id}"}
+		}, `
+See ⟪Home⟫¹, ⟪setup⟫², #𝟭𝟮³, org/repo#𝟳, @𝗮𝗹𝗶𝗰𝗲, a#1, mail@x.org, ⌜#3⌝, #4⁴ and #abc.
+
+────────────────────
+¹ https://w.example/Home
+² https://w.example/Setup%20Guide
+³ https://i.example/12
+⁴ https://x`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkOutput(t, convertTest(t, referenceText, tt.config), tt.want)
+		})
+	}
+}
+
+func TestReferenceURL(t *testing.T) {
+	tests := []struct {
+		ref  Reference
+		want string
+	}{
+		{Reference{RefType: ReferenceWiki, Target: "A/B c", Label: "x"}, "https://w.example/A%2FB%20c"},
+		{Reference{RefType: ReferenceRepoIssue, Target: "7", Repo: "org/repo"}, "https://g.example/org/repo/issues/7"},
+		{Reference{RefType: ReferenceMention, Target: "bob"}, "https://g.example/bob"},
+	}
+	for _, tt := range tests {
+		if got := tt.ref.URL(referenceTemplates); got != tt.want {
+			t.Errorf("URL of %s = %q, want %q", tt.ref.Plain(), got, tt.want)
+		}
+	}
+	issue := Reference{RefType: ReferenceIssue, Target: "1"}
+	if got := issue.URL(nil); got != "" {
+		t.Errorf("URL without templates = %q, want none", got)
+	}
+}
+
+func TestLinkTemplatesInvalid(t *testing.T) {
+	if err := checkLinkTemplates(map[string]string{"wikis": "x"}); err == nil || !strings.Contains(err.Error(), "wikis") {
+		t.Errorf("checkLinkTemplates = %v, want an unknown template", err)
+	}
+	input := "---\nunidoc:\n  link-templates:\n    bogus: https://x/{id}\n---\n#1"
+	if _, err := Convert([]byte(input), DefaultConfig()); err == nil {
+		t.Error("Convert with an unknown link template succeeded")
+	}
+}
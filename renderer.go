@@ -1,28 +1,43 @@
 package unidoc
 
 import (
+	"bufio"
 	"bytes"
+	"cmp"
+	"errors"
 	"fmt"
+	"image"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
-	"unicode/utf8"
 
-	"github.com/yuin/goldmark"
 	gast "github.com/yuin/goldmark/ast"
 	"github.com/yuin/goldmark/parser"
 	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
 	"github.com/yuin/goldmark/util"
 )
 
 // UnicodeRenderer implements a custom renderer for pure Unicode text output
 type UnicodeRenderer struct {
 	config Config
+	funcs  map[gast.NodeKind]renderer.NodeRendererFunc // Registered functions for rendering nested content
+	width  int                                         // Width available to the block being rendered
+
+	overrides []overrideState // Stack of states replaced by element attributes
+	links     []string        // Link destinations collected for footnotes
+	sections  []section       // Outline of the document
 
 	listLevel       int
 	blockquoteLevel int
 	inHeader        bool
+	headingItalic   bool      // Whether the current heading uses the italic style
+	headingStyle    TextStyle // Text style of the current heading
 	inStrong        bool
 	inItalic        bool
+	inHighlight     bool
+	inUnderline     bool
 	inListItem      bool
 	inBlockquote    bool
 	listNumbers     []int  // Stack to track current numbers for nested ordered lists
@@ -31,24 +46,49 @@ type UnicodeRenderer struct {
 
 // NewUnicodeRenderer creates a new Unicode text renderer
 func NewUnicodeRenderer(config Config) *UnicodeRenderer {
+	width := config.Width
+	if width <= 0 {
+		width = defaultWidth
+	}
 	return &UnicodeRenderer{
 		config: config,
+		funcs:  make(map[gast.NodeKind]renderer.NodeRendererFunc),
+		width:  max(width, minWidth),
 	}
 }
 
+// recordingRegisterer remembers registered functions while passing them on,
+// so that the renderer can render nested content into a separate buffer.
+type recordingRegisterer struct {
+	renderer.NodeRendererFuncRegisterer
+	funcs map[gast.NodeKind]renderer.NodeRendererFunc
+}
+
+// Register implements renderer.NodeRendererFuncRegisterer.
+func (rr recordingRegisterer) Register(kind gast.NodeKind, fn renderer.NodeRendererFunc) {
+	rr.funcs[kind] = fn
+	rr.NodeRendererFuncRegisterer.Register(kind, fn)
+}
+
 // RegisterFuncs registers the renderer for all node types
 func (r *UnicodeRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg = recordingRegisterer{reg, r.funcs}
+
 	// Block nodes
 	reg.Register(gast.KindDocument, r.renderDocument)
-	reg.Register(gast.KindHeading, r.renderHeading)
-	reg.Register(gast.KindParagraph, r.renderParagraph)
-	reg.Register(gast.KindList, r.renderList)
+	reg.Register(KindFrontMatter, r.renderFrontMatter)
+	reg.Register(KindTableOfContents, r.renderTableOfContents)
+	reg.Register(gast.KindHeading, r.withOverrides(r.renderHeading))
+	reg.Register(gast.KindParagraph, r.withOverrides(r.renderParagraph))
+	reg.Register(gast.KindList, r.withOverrides(r.renderList))
 	reg.Register(gast.KindListItem, r.renderListItem)
-	reg.Register(gast.KindBlockquote, r.renderBlockquote)
-	reg.Register(gast.KindCodeBlock, r.renderCodeBlock)
-	reg.Register(gast.KindFencedCodeBlock, r.renderCodeBlock)
+	reg.Register(gast.KindBlockquote, r.withOverrides(r.renderBlockquote))
+	reg.Register(gast.KindCodeBlock, r.withOverrides(r.renderCodeBlock))
+	reg.Register(gast.KindFencedCodeBlock, r.withOverrides(r.renderCodeBlock))
 	reg.Register(gast.KindHTMLBlock, r.renderHTMLBlock)
 	reg.Register(gast.KindThematicBreak, r.renderThematicBreak)
+	reg.Register(KindFencedDiv, r.withOverrides(r.renderFencedDiv))
+	reg.Register(KindDisplayMath, r.withOverrides(r.renderDisplayMath))
 
 	// Inline nodes
 	reg.Register(gast.KindText, r.renderText)
@@ -59,77 +99,228 @@ func (r *UnicodeRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer)
 	reg.Register(gast.KindAutoLink, r.renderAutoLink)
 	reg.Register(gast.KindRawHTML, r.renderRawHTML)
 	reg.Register(gast.KindTextBlock, r.renderTextBlock)
+	reg.Register(KindInlineMath, r.renderInlineMath)
+	reg.Register(KindSuperscript, r.renderSuperscript)
+	reg.Register(KindSubscript, r.renderSubscript)
+	reg.Register(KindHighlight, r.renderHighlight)
+	reg.Register(KindInserted, r.renderInserted)
+	reg.Register(KindReference, r.renderReference)
 }
 
-// Document renderer
-func (r *UnicodeRenderer) renderDocument(
-	_ util.BufWriter,
-	_ []byte,
-	_ gast.Node,
-	_ bool,
-) (gast.WalkStatus, error) {
-	return gast.WalkContinue, nil
+// renderChildren renders the children of node into a string, laying them out
+// within the given width.
+func (r *UnicodeRenderer) renderChildren(source []byte, node gast.Node, width int) (string, error) {
+	saved := r.width
+	r.width = max(width, minWidth)
+	defer func() { r.width = saved }()
+
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+	for child := node.FirstChild(); child != nil; child = child.NextSibling() {
+		err := gast.Walk(child, func(n gast.Node, entering bool) (gast.WalkStatus, error) {
+			if fn := r.funcs[n.Kind()]; fn != nil {
+				return fn(w, source, n, entering)
+			}
+			return gast.WalkContinue, nil
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return "", err
+	}
+
+	return cleanupOutput(buf.String()), nil
 }
 
-// Heading renderer
-func (r *UnicodeRenderer) renderHeading(
+// strongText applies the configured strong style to text.
+func (r *UnicodeRenderer) strongText(text string) string {
+	switch r.config.StrongStyle {
+	case StrongStylePlain:
+		// Use plain strong style, no conversion needed
+	case StrongStyleMarkers:
+		// Use simple markers for strong text
+		text = fmt.Sprintf("**%s**", text)
+	case StrongStyleBoldSansSerif:
+		// Use mathematical bold sans-serif for strong text
+		text = toBoldSansSerifText(text)
+	}
+	return text
+}
+
+// headingText applies the style of the current heading to text.
+func (r *UnicodeRenderer) headingText(text string) string {
+	if r.headingItalic {
+		return r.italicText(text)
+	}
+	return r.headingStyle.apply(text)
+}
+
+// italicText applies the configured italic style to text.
+func (r *UnicodeRenderer) italicText(text string) string {
+	switch r.config.ItalicStyle {
+	case ItalicStylePlain:
+		// Use plain italic style, no conversion needed
+	case ItalicStyleMarkers:
+		// Use simple markers for italic text
+		text = fmt.Sprintf("*%s*", text)
+	case ItalicStyleScript:
+		text = toItalicScriptText(text)
+	case ItalicStyleSlantedSansSerif:
+		text = toSlantedSansSerifText(text)
+	}
+	return text
+}
+
+// Document renderer
+func (r *UnicodeRenderer) renderDocument(
 	w util.BufWriter,
-	_ []byte,
+	source []byte,
 	node gast.Node,
 	entering bool,
 ) (gast.WalkStatus, error) {
-	n := node.(*gast.Heading)
 	if entering {
-		r.inHeader = true
-		// Render headers with Unicode box drawing characters and emphasis
-		level := n.Level
-		var prefix string
-		switch level {
-		case 1:
-			prefix = "█" // Full block for H1
-		case 2:
-			prefix = "▓" // Medium shade for H2
-		case 3:
-			prefix = "▒" // Light shade for H3
-		case 4:
-			prefix = "░" // Very light shade for H4
-		case 5:
-			prefix = "▫" // Small square for H5
-		default:
-			prefix = "▪" // Small black square for H6+
-		}
-
-		if level > 1 && level < 5 {
-			prefix = strings.Repeat(prefix, level)
-		}
-
-		if _, err := w.WriteString(prefix + " "); err != nil {
-			return gast.WalkStop, err
-		}
-	} else {
-		r.inHeader = false
-		// Add underline for H1 and H2
-		if n.Level <= 2 {
-			if _, err := w.WriteString("\n"); err != nil {
-				return gast.WalkStop, err
-			}
-			char := "═"
-			if n.Level == 2 {
-				char = "─"
+		r.sections = outline(node, source)
+	} else if len(r.links) > 0 {
+		// List the link destinations referenced by footnote markers
+		var b strings.Builder
+		b.WriteString("\n" + strings.Repeat("─", 20) + "\n")
+		for i, url := range r.links {
+			b.WriteString(footnoteMarker(i+1) + " " + url + "\n")
+			if r.config.LinkStyle != LinkStyleQR {
+				continue
 			}
-			// Estimate header length (rough approximation)
-			underline := strings.Repeat(char, 50)
-			if _, err := w.WriteString(underline); err != nil {
-				return gast.WalkStop, err
+			// Codes that do not fit leave the URL on its own
+			if modules, err := encodeQR([]byte(url), qrLevelM); err == nil && len(modules)+8 <= r.width {
+				for _, line := range drawQR(modules, 4, false) {
+					b.WriteString(line + "\n")
+				}
 			}
 		}
-		if _, err := w.WriteString("\n\n"); err != nil {
+		if _, err := w.WriteString(b.String()); err != nil {
 			return gast.WalkStop, err
 		}
 	}
 	return gast.WalkContinue, nil
 }
 
+// headingBlock lays out the rendered lines of a heading according to its
+// style.
+func (r *UnicodeRenderer) headingBlock(lines []string, style HeadingStyle) string {
+	// repeat fills a width with copies of a possibly wide character
+	repeat := func(char string, width int) string {
+		return strings.Repeat(char, width/max(textWidth(char), 1))
+	}
+	widest := 0
+	for _, line := range lines {
+		widest = max(widest, textWidth(line))
+	}
+
+	var b strings.Builder
+	switch style.Frame {
+	case HeadingFrameBox:
+		// Long headings wrap inside the box rather than being cut off
+		var wrapped []string
+		for _, line := range lines {
+			wrapped = append(wrapped, wrapLine(line, r.width-4)...)
+		}
+		widest = 0
+		for _, line := range wrapped {
+			widest = max(widest, textWidth(line))
+		}
+		b.WriteString(drawBox(wrapped, min(widest+4, r.width), "", BoxStyleDouble))
+	case HeadingFrameBanner:
+		// Lines are centered as a block, keeping the letters of banner
+		// fonts in place
+		rule := repeat(cmp.Or(style.Underline, "═"), r.width)
+		indent := strings.Repeat(" ", max((r.width-widest)/2, 0))
+		b.WriteString(rule + "\n")
+		for _, line := range lines {
+			b.WriteString(strings.TrimRight(indent+line, " ") + "\n")
+		}
+		b.WriteString(rule + "\n")
+	default:
+		b.WriteString(strings.Join(lines, "\n") + "\n")
+		if style.Underline != "" {
+			switch style.UnderlineLength {
+			case RuleLengthText:
+				b.WriteString(repeat(style.Underline, widest) + "\n")
+			case RuleLengthFull:
+				b.WriteString(repeat(style.Underline, r.width) + "\n")
+			}
+		}
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// Heading renderer
+func (r *UnicodeRenderer) renderHeading(
+	w util.BufWriter,
+	source []byte,
+	node gast.Node,
+	entering bool,
+) (gast.WalkStatus, error) {
+	if !entering {
+		return gast.WalkContinue, nil
+	}
+
+	n := node.(*gast.Heading)
+	style := r.config.Headings.level(n.Level)
+	if style.Font != BannerFontNone {
+		return r.renderBannerHeading(w, source, n, style)
+	}
+
+	// Render the heading text first, so that it can be measured and framed
+	r.inHeader, r.headingStyle = true, style.Text
+	_, r.headingItalic = italicClass(n)
+	r.headingItalic = r.headingItalic && !r.config.IgnoreOverrides
+	text, err := r.renderChildren(source, n, r.width)
+	r.inHeader = false
+	if err != nil {
+		return gast.WalkStop, err
+	}
+
+	line := text
+	if s, ok := r.findSection(n); ok && r.config.NumberHeadings {
+		line = r.headingText(r.sectionLabel(s)) + " " + line
+	}
+	if style.Prefix != "" {
+		line = style.Prefix + " " + line
+	}
+
+	if _, err := w.WriteString(r.headingBlock([]string{line}, style)); err != nil {
+		return gast.WalkStop, err
+	}
+	return gast.WalkSkipChildren, nil
+}
+
+// renderBannerHeading renders a heading as large letters in the bitmap font
+// of its style, from the plain heading text with its section number. The
+// prefix is left out.
+func (r *UnicodeRenderer) renderBannerHeading(
+	w util.BufWriter,
+	source []byte,
+	n *gast.Heading,
+	style HeadingStyle,
+) (gast.WalkStatus, error) {
+	text := plainText(n, source)
+	if s, ok := r.findSection(n); ok && r.config.NumberHeadings {
+		text = r.sectionLabel(s) + " " + text
+	}
+
+	width := r.width
+	if style.Frame == HeadingFrameBox {
+		width -= 4
+	}
+	lines := drawBanner(text, style.Font, width, false)
+	if _, err := w.WriteString(r.headingBlock(lines, style)); err != nil {
+		return gast.WalkStop, err
+	}
+	return gast.WalkSkipChildren, nil
+}
+
 // Paragraph renderer
 func (r *UnicodeRenderer) renderParagraph(
 	w util.BufWriter,
@@ -167,31 +358,19 @@ func (r *UnicodeRenderer) renderText(
 
 	switch {
 	case r.inHeader:
-		text = toBoldSansSerifText(text)
+		text = r.headingText(text)
 	case r.inStrong:
-		switch r.config.StrongStyle {
-		case StrongStylePlain:
-			// Use plain strong style, no conversion needed
-		case StrongStyleMarkers:
-			// Use simple markers for strong text
-			text = fmt.Sprintf("**%s**", text)
-		case StrongStyleBoldSansSerif:
-			// Use mathematical bold sans-serif for strong text
-			text = toBoldSansSerifText(text)
-		}
+		text = r.strongText(text)
 
 	case r.inItalic:
-		switch r.config.ItalicStyle {
-		case ItalicStylePlain:
-			// Use plain italic style, no conversion needed
-		case ItalicStyleMarkers:
-			// Use simple markers for italic text
-			text = fmt.Sprintf("*%s*", text)
-		case ItalicStyleScript:
-			text = toItalicScriptText(text)
-		case ItalicStyleSlantedSansSerif:
-			text = toSlantedSansSerifText(text)
-		}
+		text = r.italicText(text)
+	}
+
+	if r.inHighlight {
+		text = combineText(text, r.config.HighlightStyle.combiningMark())
+	}
+	if r.inUnderline {
+		text = combineText(text, r.config.UnderlineStyle.combiningMark())
 	}
 
 	if _, err := w.WriteString(text); err != nil {
@@ -244,11 +423,14 @@ func (r *UnicodeRenderer) renderEmphasis(
 // List renderer
 func (r *UnicodeRenderer) renderList(
 	w util.BufWriter,
-	_ []byte,
+	source []byte,
 	node gast.Node,
 	entering bool,
 ) (gast.WalkStatus, error) {
 	n := node.(*gast.List)
+	if hasClass(n, "tree") && checkTreeAnnotation(treeListAnnotation(n)) == nil {
+		return r.renderTreeList(w, source, n, entering)
+	}
 
 	if entering {
 		// Add newline before nested lists (but not for top-level lists)
@@ -317,7 +499,10 @@ func (r *UnicodeRenderer) renderListItem(
 			}
 		} else {
 			// Use Unicode bullets for unordered lists
-			bullets := []string{"•", "◦", "▪", "▫", "‣", "⁃"}
+			bullets := r.config.Bullets
+			if len(bullets) == 0 {
+				bullets = DefaultConfig().Bullets
+			}
 			bullet := bullets[(r.listLevel-1)%len(bullets)]
 			if _, err := w.WriteString(fmt.Sprintf("%s%s ", indent, bullet)); err != nil {
 				return gast.WalkStop, err
@@ -395,6 +580,14 @@ func (r *UnicodeRenderer) renderCodeBlock(
 			buf.Write(line.Value(source))
 		}
 		code = buf.String()
+
+		language := strings.ToLower(string(fcb.Language(source)))
+		if processor := r.config.BlockProcessors[language]; processor != nil {
+			block := FencedBlock{Language: language, Content: code, Attributes: blockAttributes(fcb)}
+			if status, err := r.renderProcessedBlock(w, block, processor); !errors.Is(err, ErrUnprocessed) {
+				return status, err
+			}
+		}
 	} else {
 		// Regular code block
 		var buf bytes.Buffer
@@ -408,9 +601,10 @@ func (r *UnicodeRenderer) renderCodeBlock(
 	}
 
 	lines := strings.Split(strings.TrimRight(code, "\n"), "\n")
+	inner := r.width - 2
 
 	// Top border
-	if _, err := w.WriteString("┌" + strings.Repeat("─", 64) + "┐\n"); err != nil {
+	if _, err := w.WriteString("┌" + strings.Repeat("─", inner) + "┐\n"); err != nil {
 		return gast.WalkStop, err
 	}
 
@@ -420,17 +614,16 @@ func (r *UnicodeRenderer) renderCodeBlock(
 		line = strings.ReplaceAll(line, "\t", "    ")
 
 		// Ensure line doesn't exceed box width
-		if utf8.RuneCountInString(line) > 58 {
-			line = line[:58] + "…"
+		if textWidth(line) > inner-6 {
+			line = truncateText(line, inner-5)
 		}
-		padding := 64 - utf8.RuneCountInString(line)
-		if _, err := w.WriteString("│ " + line + strings.Repeat(" ", padding-2) + " │\n"); err != nil {
+		if _, err := w.WriteString("│ " + padRight(line, inner-2) + " │\n"); err != nil {
 			return gast.WalkStop, err
 		}
 	}
 
 	// Bottom border
-	if _, err := w.WriteString("└" + strings.Repeat("─", 64) + "┘\n\n"); err != nil {
+	if _, err := w.WriteString("└" + strings.Repeat("─", inner) + "┘\n\n"); err != nil {
 		return gast.WalkStop, err
 	}
 
@@ -456,6 +649,41 @@ func (r *UnicodeRenderer) renderCodeSpan(
 	return gast.WalkContinue, nil
 }
 
+// footnoteMarker returns the superscript reference number for a footnote.
+func footnoteMarker(num int) string {
+	marker, _ := toSuperscriptText(strconv.Itoa(num))
+	return marker
+}
+
+// linkPrefix returns the text preceding the text of a link in the configured
+// link style.
+func (r *UnicodeRenderer) linkPrefix() string {
+	if r.config.LinkStyle == LinkStyleEmoji {
+		return "["
+	}
+	return ""
+}
+
+// linkSuffix returns the text following the text of a link to url in the
+// configured link style.
+func (r *UnicodeRenderer) linkSuffix(url string) string {
+	switch r.config.LinkStyle {
+	case LinkStyleEmoji:
+		return fmt.Sprintf("] 🔗 <%s>", url)
+	case LinkStyleInline:
+		return fmt.Sprintf(" <%s>", url)
+	case LinkStyleFootnote, LinkStyleQR:
+		num := slices.Index(r.links, url) + 1
+		if num == 0 {
+			r.links = append(r.links, url)
+			num = len(r.links)
+		}
+		return footnoteMarker(num)
+	default:
+		return ""
+	}
+}
+
 // Link renderer
 func (r *UnicodeRenderer) renderLink(
 	w util.BufWriter,
@@ -463,15 +691,24 @@ func (r *UnicodeRenderer) renderLink(
 	node gast.Node,
 	entering bool,
 ) (gast.WalkStatus, error) {
+	n := node.(*gast.Link)
+	if ref := r.sectionReference(n.Destination); ref != "" {
+		// Internal links refer to the section of the heading instead
+		if !entering {
+			if _, err := w.WriteString(ref); err != nil {
+				return gast.WalkStop, err
+			}
+		}
+		return gast.WalkContinue, nil
+	}
+
 	if entering {
-		// Use Unicode arrow for links
-		if _, err := w.WriteString("["); err != nil {
-			return gast.WalkContinue, err
+		if _, err := w.WriteString(r.linkPrefix()); err != nil {
+			return gast.WalkStop, err
 		}
 	} else {
-		n := node.(*gast.Link)
 		url := string(n.Destination)
-		if _, err := w.WriteString(fmt.Sprintf("] 🔗 <%s>", url)); err != nil {
+		if _, err := w.WriteString(r.linkSuffix(url)); err != nil {
 			return gast.WalkStop, err
 		}
 	}
@@ -481,12 +718,21 @@ func (r *UnicodeRenderer) renderLink(
 // Image renderer
 func (r *UnicodeRenderer) renderImage(
 	w util.BufWriter,
-	_ []byte,
+	source []byte,
 	node gast.Node,
 	entering bool,
 ) (gast.WalkStatus, error) {
 	if entering {
 		n := node.(*gast.Image)
+		if r.config.ImageStyle != ImageStyleText {
+			if img, ok := loadLocalImage(string(n.Destination), r.config.ImageDir); ok {
+				if _, err := w.WriteString(r.imageBlock(img, cmp.Or(plainText(n, source), string(n.Title)), node)); err != nil {
+					return gast.WalkStop, err
+				}
+				return gast.WalkSkipChildren, nil
+			}
+		}
+
 		alt := string(n.Title)
 		if alt == "" {
 			alt = "Image"
@@ -499,6 +745,33 @@ func (r *UnicodeRenderer) renderImage(
 	return gast.WalkSkipChildren, nil
 }
 
+// imageBlock draws a local image on lines of its own, with the alt text
+// centered below as a caption.
+func (r *UnicodeRenderer) imageBlock(img image.Image, caption string, node gast.Node) string {
+	columns := r.width
+	if r.config.ImageWidth > 0 {
+		columns = min(r.config.ImageWidth, r.width)
+	}
+	lines := drawImage(img, r.config.ImageStyle, columns)
+	if caption != "" {
+		width := 0
+		for _, line := range lines {
+			width = max(width, textWidth(line))
+		}
+		lines = append(lines, strings.TrimRight(centerText("🖼️  "+caption, width), " "))
+	}
+
+	var b strings.Builder
+	if node.PreviousSibling() != nil {
+		b.WriteString("\n")
+	}
+	b.WriteString(strings.Join(lines, "\n"))
+	if node.NextSibling() != nil {
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
 // ThematicBreak renderer (horizontal rule)
 func (r *UnicodeRenderer) renderThematicBreak(
 	w util.BufWriter,
@@ -553,23 +826,35 @@ func (r *UnicodeRenderer) renderTextBlock(
 	return gast.WalkContinue, nil
 }
 
-// getOrderedMarker returns fancy Unicode numbering based on the number and nesting level
+// getOrderedMarker returns fancy Unicode numbering based on the number and
+// the configured style for the nesting level
 func (r *UnicodeRenderer) getOrderedMarker(num int, level int) string {
-	switch level {
-	case 1:
-		// Level 1: Circled numbers ① ② ③ etc.
+	styles := r.config.NumberStyles
+	if len(styles) == 0 {
+		styles = DefaultConfig().NumberStyles
+	}
+	style := NumberStyleAngle // Levels beyond the styles read ⟨1⟩ ⟨2⟩
+	if level <= len(styles) {
+		style = styles[level-1]
+	}
+
+	switch style {
+	case NumberStyleDecimal:
+		return fmt.Sprintf("%d.", num)
+	case NumberStyleCircled:
+		// Circled numbers ① ② ③ etc.
 		return r.getCircledNumber(num)
-	case 2:
-		// Level 2: Parenthesized numbers ⑴ ⑵ ⑶ etc.
+	case NumberStyleParenthesized:
+		// Parenthesized numbers ⑴ ⑵ ⑶ etc.
 		return r.getParenthesizedNumber(num)
-	case 3:
-		// Level 3: Circled letters 🅐 🅑 🅒 etc.
+	case NumberStyleCircledLetter:
+		// Circled letters 🅐 🅑 🅒 etc.
 		return r.getCircledLetter(num)
-	case 4:
-		// Level 4: Roman numerals ⅰ ⅱ ⅲ etc.
+	case NumberStyleRoman:
+		// Roman numerals ⅰ ⅱ ⅲ etc.
 		return r.getRomanNumeral(num, false) // lowercase
-	case 5:
-		// Level 5: Roman numerals uppercase Ⅰ Ⅱ Ⅲ etc.
+	case NumberStyleUpperRoman:
+		// Roman numerals uppercase Ⅰ Ⅱ Ⅲ etc.
 		return r.getRomanNumeral(num, true) // uppercase
 	default:
 		// Fallback: regular numbers with fancy formatting
@@ -674,32 +959,112 @@ func (r *UnicodeRenderer) toSmartDashes(text string) string {
 	return text
 }
 
-// Convert converts Markdown text to Unicode-rendered text
-func Convert(inp []byte, config Config) (string, error) {
-	// Create goldmark instance with Unicode renderer
-	md := goldmark.New(
-		goldmark.WithParserOptions(
-			parser.WithAutoHeadingID(),
+// Result holds the output of ConvertDocument.
+type Result struct {
+	Text     string    // Unicode-rendered text
+	Metadata *Metadata // Front matter of the document, nil if there is none
+}
+
+// newParser creates a goldmark parser with the extensions of the Unicode
+// renderer.
+func newParser() parser.Parser {
+	return parser.NewParser(
+		parser.WithBlockParsers(parser.DefaultBlockParsers()...),
+		parser.WithInlineParsers(parser.DefaultInlineParsers()...),
+		parser.WithParagraphTransformers(parser.DefaultParagraphTransformers()...),
+		parser.WithAutoHeadingID(),
+		parser.WithHeadingAttribute(),
+		parser.WithBlockParsers(
+			util.Prioritized(newFrontMatterParser(), 50),
+			util.Prioritized(newFencedDivParser(), 800),
+			util.Prioritized(newDisplayMathParser(), 800),
 		),
-		goldmark.WithRenderer(
-			renderer.NewRenderer(
-				renderer.WithNodeRenderers(
-					util.Prioritized(NewUnicodeRenderer(config), 1000),
-				),
-			),
+		parser.WithInlineParsers(
+			util.Prioritized(newReferenceParser(), 150), // Ahead of links for [[wiki links]]
+			util.Prioritized(newInlineMathParser(), 500),
+			util.Prioritized(newScriptParser(), 500),
+			util.Prioritized(newMarkParser('='), 500),
+			util.Prioritized(newMarkParser('+'), 500),
+		),
+		parser.WithASTTransformers(
+			util.Prioritized(newHTMLScriptTransformer(), 500),
+			util.Prioritized(newAttributeTransformer(), 500),
+			util.Prioritized(newTOCTransformer(), 500),
 		),
 	)
+}
+
+// newRenderer creates a goldmark renderer with the Unicode renderer.
+func newRenderer(config Config) renderer.Renderer {
+	return renderer.NewRenderer(
+		renderer.WithNodeRenderers(
+			util.Prioritized(NewUnicodeRenderer(config), 1000),
+		),
+	)
+}
+
+// parseDocument parses a document, merges the rendering options of its front
+// matter onto config and applies the section filters.
+func parseDocument(inp []byte, config *Config) (gast.Node, *Metadata, error) {
+	doc := newParser().Parse(text.NewReader(inp))
+
+	var meta *Metadata
+	if fm, ok := doc.FirstChild().(*FrontMatter); ok {
+		meta = fm.Metadata
+		if !config.IgnoreOverrides {
+			if err := config.applyOptions(fm.Metadata.Options); err != nil {
+				return nil, nil, fmt.Errorf("invalid front matter: %w", err)
+			}
+		}
+	}
+
+	if err := checkLinkTemplates(config.LinkTemplates); err != nil {
+		return nil, nil, err
+	}
+	if err := config.registerFilters(); err != nil {
+		return nil, nil, err
+	}
+	if err := filterSections(doc, inp, *config); err != nil {
+		return nil, nil, err
+	}
+	if config.TOC {
+		insertTableOfContents(doc)
+	}
+	return doc, meta, nil
+}
+
+// ConvertDocument converts Markdown text to Unicode-rendered text and
+// returns it together with the metadata from the document's front matter.
+// Rendering options under the unidoc key of the front matter are merged
+// onto config unless config.IgnoreOverrides is set.
+func ConvertDocument(inp []byte, config Config) (Result, error) {
+	doc, meta, err := parseDocument(inp, &config)
+	if err != nil {
+		return Result{}, err
+	}
 
 	var buf bytes.Buffer
-	if err := md.Convert(inp, &buf); err != nil {
-		return "", fmt.Errorf("failed to convert markdown: %w", err)
+	if err := newRenderer(config).Render(&buf, inp, doc); err != nil {
+		return Result{}, fmt.Errorf("failed to convert markdown: %w", err)
 	}
 
-	// Clean up multiple consecutive newlines
-	result := buf.String()
-	re := regexp.MustCompile(`\n{3,}`)
-	result = re.ReplaceAllString(result, "\n\n")
+	return Result{Text: cleanupOutput(buf.String()), Metadata: meta}, nil
+}
+
+// Convert converts Markdown text to Unicode-rendered text
+func Convert(inp []byte, config Config) (string, error) {
+	result, err := ConvertDocument(inp, config)
+	return result.Text, err
+}
 
+// excessNewlines matches runs of blank lines.
+var excessNewlines = regexp.MustCompile(`\n{3,}`)
+
+// cleanupOutput collapses runs of blank lines and trims surrounding whitespace.
+func cleanupOutput(result string) string {
 	// Clean up multiple consecutive newlines
-	return strings.TrimSpace(result), nil
+	result = excessNewlines.ReplaceAllString(result, "\n\n")
+
+	// Keep the indentation of the first line, which may be centered content
+	return strings.TrimRight(strings.TrimLeft(result, "\n"), " \t\n")
 }
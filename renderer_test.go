@@ -0,0 +1,25 @@
+package unidoc
+
+import "testing"
+
+func TestZeroConfig(t *testing.T) {
+	// Lists and headings without configured styles render with the defaults
+	input := "# Title\n\n## Part\n\n1. a\n   1. b\n\n- c\n  - d"
+	got, err := Convert([]byte(input), Config{Width: 30})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkOutput(t, got, convertTest(t, input))
+	checkOutput(t, got, `
+█ 𝗧𝗶𝘁𝗹𝗲
+═══════
+
+▓▓ 𝗣𝗮𝗿𝘁
+───────
+
+① a
+  ⑴ b
+
+• c
+  ◦ d`)
+}
@@ -0,0 +1,237 @@
+package unidoc
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// A Superscript represents superscript text: ^text^ or <sup>text</sup>.
+type Superscript struct {
+	gast.BaseInline
+	Value string // Text to raise
+}
+
+// KindSuperscript is a NodeKind of the Superscript node.
+var KindSuperscript = gast.NewNodeKind("Superscript")
+
+// Kind implements Node.Kind.
+func (n *Superscript) Kind() gast.NodeKind {
+	return KindSuperscript
+}
+
+// Dump implements Node.Dump.
+func (n *Superscript) Dump(source []byte, level int) {
+	gast.DumpHelper(n, source, level, map[string]string{"Value": n.Value}, nil)
+}
+
+// A Subscript represents subscript text: ~text~ or <sub>text</sub>.
+type Subscript struct {
+	gast.BaseInline
+	Value string // Text to lower
+}
+
+// KindSubscript is a NodeKind of the Subscript node.
+var KindSubscript = gast.NewNodeKind("Subscript")
+
+// Kind implements Node.Kind.
+func (n *Subscript) Kind() gast.NodeKind {
+	return KindSubscript
+}
+
+// Dump implements Node.Dump.
+func (n *Subscript) Dump(source []byte, level int) {
+	gast.DumpHelper(n, source, level, map[string]string{"Value": n.Value}, nil)
+}
+
+// newScriptNode returns a Superscript for '^' and a Subscript for '~'.
+func newScriptNode(char byte, text string) gast.Node {
+	if char == '^' {
+		return &Superscript{Value: text}
+	}
+	return &Subscript{Value: text}
+}
+
+type scriptParser struct{}
+
+// newScriptParser returns a new InlineParser that parses ^superscript^ and
+// ~subscript~ spans.
+func newScriptParser() parser.InlineParser {
+	return &scriptParser{}
+}
+
+func (s *scriptParser) Trigger() []byte {
+	return []byte{'^', '~'}
+}
+
+// Parse follows the Pandoc rules: the content must not be empty and must not
+// contain unescaped whitespace, and doubled delimiters are left alone so that
+// ~~strikethrough~~ keeps working.
+func (s *scriptParser) Parse(_ gast.Node, block text.Reader, _ parser.Context) gast.Node {
+	line, _ := block.PeekLine()
+	char := line[0]
+	if len(line) < 3 || line[1] == char || block.PrecendingCharacter() == rune(char) {
+		return nil
+	}
+
+	for i := 1; i < len(line); i++ {
+		switch c := line[i]; {
+		case c == '\\' && i+1 < len(line):
+			i++ // skip escaped character
+		case isSpaceByte(c):
+			return nil
+		case c == char:
+			if i+1 < len(line) && line[i+1] == char {
+				return nil
+			}
+			content := strings.ReplaceAll(string(line[1:i]), `\ `, " ")
+			block.Advance(i + 1)
+			return newScriptNode(char, content)
+		}
+	}
+	return nil
+}
+
+func (s *scriptParser) CloseBlock(_ gast.Node, _ parser.Context) {
+	// nothing to do
+}
+
+// htmlScriptTransformer replaces <sup>...</sup> and <sub>...</sub> raw HTML
+// pairs with Superscript and Subscript nodes.
+type htmlScriptTransformer struct{}
+
+// newHTMLScriptTransformer returns a new ASTTransformer for HTML script tags.
+func newHTMLScriptTransformer() parser.ASTTransformer {
+	return &htmlScriptTransformer{}
+}
+
+// rawHTMLTag returns the lowercased source of a raw HTML node.
+func rawHTMLTag(n *gast.RawHTML, source []byte) string {
+	var b strings.Builder
+	for i := 0; i < n.Segments.Len(); i++ {
+		segment := n.Segments.At(i)
+		b.Write(segment.Value(source))
+	}
+	return strings.ToLower(strings.TrimSpace(b.String()))
+}
+
+func (t *htmlScriptTransformer) Transform(doc *gast.Document, reader text.Reader, _ parser.Context) {
+	source := reader.Source()
+
+	var opening []*gast.RawHTML
+	_ = gast.Walk(doc, func(n gast.Node, entering bool) (gast.WalkStatus, error) {
+		if raw, ok := n.(*gast.RawHTML); ok && entering {
+			if tag := rawHTMLTag(raw, source); tag == "<sup>" || tag == "<sub>" {
+				opening = append(opening, raw)
+			}
+		}
+		return gast.WalkContinue, nil
+	})
+
+	for _, open := range opening {
+		parent := open.Parent()
+		if parent == nil {
+			continue
+		}
+		closeTag := "</" + rawHTMLTag(open, source)[1:]
+
+		// Collect the text up to the matching closing tag
+		var (
+			b       strings.Builder
+			content []gast.Node
+			closing gast.Node
+		)
+		for n := open.NextSibling(); n != nil; n = n.NextSibling() {
+			if raw, ok := n.(*gast.RawHTML); ok && rawHTMLTag(raw, source) == closeTag {
+				closing = n
+				break
+			}
+			b.WriteString(plainText(n, source))
+			content = append(content, n)
+		}
+		if closing == nil {
+			continue
+		}
+
+		char := byte('^')
+		if closeTag == "</sub>" {
+			char = '~'
+		}
+		parent.InsertBefore(parent, open, newScriptNode(char, b.String()))
+		for _, n := range append(content, open, closing) {
+			parent.RemoveChild(parent, n)
+		}
+	}
+}
+
+// plainText returns the text content of a node and its descendants.
+func plainText(node gast.Node, source []byte) string {
+	var b strings.Builder
+	_ = gast.Walk(node, func(n gast.Node, entering bool) (gast.WalkStatus, error) {
+		if !entering {
+			return gast.WalkContinue, nil
+		}
+		switch n := n.(type) {
+		case *gast.Text:
+			b.Write(n.Segment.Value(source))
+			if n.SoftLineBreak() {
+				b.WriteByte(' ')
+			}
+		case *gast.String:
+			b.Write(n.Value)
+		case *Reference:
+			b.WriteString(n.Plain())
+		}
+		return gast.WalkContinue, nil
+	})
+	return b.String()
+}
+
+// scriptText converts text to a superscript or subscript using translate,
+// falling back to marker notation such as ^x or _(ij) for text without a
+// Unicode equivalent.
+func scriptText(s string, translate func(string) (string, bool), marker string) string {
+	if out, ok := translate(s); ok {
+		return out
+	}
+	if utf8.RuneCountInString(s) == 1 {
+		return marker + s
+	}
+	return marker + "(" + s + ")"
+}
+
+// Superscript renderer
+func (r *UnicodeRenderer) renderSuperscript(
+	w util.BufWriter,
+	_ []byte,
+	node gast.Node,
+	entering bool,
+) (gast.WalkStatus, error) {
+	if entering {
+		n := node.(*Superscript)
+		if _, err := w.WriteString(scriptText(n.Value, toSuperscriptText, "^")); err != nil {
+			return gast.WalkStop, err
+		}
+	}
+	return gast.WalkSkipChildren, nil
+}
+
+// Subscript renderer
+func (r *UnicodeRenderer) renderSubscript(
+	w util.BufWriter,
+	_ []byte,
+	node gast.Node,
+	entering bool,
+) (gast.WalkStatus, error) {
+	if entering {
+		n := node.(*Subscript)
+		if _, err := w.WriteString(scriptText(n.Value, toSubscriptText, "_")); err != nil {
+			return gast.WalkStop, err
+		}
+	}
+	return gast.WalkSkipChildren, nil
+}
@@ -0,0 +1,33 @@
+package unidoc
+
+import "testing"
+
+func TestScripts(t *testing.T) {
+	tests := []struct {
+		input, want string
+	}{
+		{"H~2~O", "H₂O"},
+		{"x^2^ and x^n+1^", "x² and xⁿ⁺¹"},
+		{"note^ref^", "noteʳᵉᶠ"},
+		{"2^10^", "2¹⁰"},
+		{`x^a\ b^`, "xᵃ ᵇ"},
+		{"10<sup>-3</sup> m", "10⁻³ m"},
+		{"H<sub>2</sub>O", "H₂O"},
+
+		// Characters without a Unicode form keep a marker
+		{"x^qz^", "x^(qz)"},
+		{"H~(aq)~", "H_((aq))"},
+
+		// Spaces, empty spans and doubled delimiters are not scripts
+		{"a^b c^ d", "a^b c^ d"},
+		{"a ~ b ~ c", "a ~ b ~ c"},
+		{"x^^", "x^^"},
+		{"~~strike~~", "~~strike~~"},
+		{"a <sup>x", "a x"},
+	}
+	for _, tt := range tests {
+		if got := convertTest(t, tt.input); got != tt.want {
+			t.Errorf("Convert(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
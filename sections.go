@@ -0,0 +1,146 @@
+package unidoc
+
+import (
+	"fmt"
+	"strings"
+
+	gast "github.com/yuin/goldmark/ast"
+)
+
+// headingID returns the ID of a heading, or "" if it has none.
+func headingID(heading *gast.Heading) string {
+	if id, ok := heading.AttributeString("id"); ok {
+		if id, ok := id.([]byte); ok {
+			return string(id)
+		}
+	}
+	return ""
+}
+
+// extractSection removes everything but the section of the first top-level
+// heading accepted by match: the heading and the blocks up to the next
+// heading of the same or a higher level. It reports whether a heading
+// matched.
+func extractSection(doc gast.Node, match func(*gast.Heading) bool) bool {
+	var (
+		keep  []gast.Node
+		level int // Level of the matched heading, 0 before the match
+	)
+	for child := doc.FirstChild(); child != nil; child = child.NextSibling() {
+		if heading, ok := child.(*gast.Heading); ok {
+			if level == 0 && match(heading) {
+				level = heading.Level
+			} else if level != 0 && heading.Level <= level {
+				break
+			}
+		}
+		if level != 0 {
+			keep = append(keep, child)
+		}
+	}
+	if level == 0 {
+		return false
+	}
+
+	doc.RemoveChildren(doc)
+	for _, child := range keep {
+		doc.AppendChild(doc, child)
+	}
+	return true
+}
+
+// dropDeepSections removes the top-level headings deeper than maxLevel
+// together with the blocks that follow them.
+func dropDeepSections(doc gast.Node, maxLevel int) {
+	dropping := false
+	for child := doc.FirstChild(); child != nil; {
+		next := child.NextSibling()
+		if heading, ok := child.(*gast.Heading); ok {
+			dropping = heading.Level > maxLevel
+		}
+		if dropping {
+			doc.RemoveChild(doc, child)
+		}
+		child = next
+	}
+}
+
+// shiftHeadings moves all headings of a document by shift levels, keeping
+// them between level 1 and 6.
+func shiftHeadings(doc gast.Node, shift int) {
+	_ = gast.Walk(doc, func(n gast.Node, entering bool) (gast.WalkStatus, error) {
+		if heading, ok := n.(*gast.Heading); ok && entering {
+			heading.Level = min(max(heading.Level+shift, 1), 6)
+		}
+		return gast.WalkContinue, nil
+	})
+}
+
+// filterSections applies the section filters of a configuration to a
+// document: section extraction, the maximum heading level and the heading
+// shift, in this order.
+func filterSections(doc gast.Node, source []byte, config Config) error {
+	if config.Section != "" {
+		found := extractSection(doc, func(heading *gast.Heading) bool {
+			return strings.EqualFold(strings.TrimSpace(plainText(heading, source)), strings.TrimSpace(config.Section))
+		})
+		if !found {
+			return fmt.Errorf("section not found: %s", config.Section)
+		}
+	}
+	if config.SectionID != "" {
+		found := extractSection(doc, func(heading *gast.Heading) bool {
+			return headingID(heading) == config.SectionID
+		})
+		if !found {
+			return fmt.Errorf("section not found: #%s", config.SectionID)
+		}
+	}
+	if config.MaxHeadingLevel > 0 {
+		dropDeepSections(doc, config.MaxHeadingLevel)
+	}
+	if config.ShiftHeadings != 0 {
+		shiftHeadings(doc, config.ShiftHeadings)
+	}
+	return nil
+}
+
+// outlineEntries lays out the sections of the document up to a depth as
+// indented entries led by the heading prefix, with a dotted leader to the
+// text returned by right.
+func (r *UnicodeRenderer) outlineEntries(depth int, right func(section) string) []string {
+	var entries []string
+	for _, section := range r.sections {
+		if depth > 0 && section.depth >= depth {
+			continue
+		}
+
+		label := right(section)
+		entry := strings.Repeat("  ", section.depth)
+		if prefix := r.config.Headings.level(section.heading.Level).Prefix; prefix != "" {
+			entry += prefix + " "
+		}
+		room := r.width - textWidth(entry) - textWidth(label) - 4
+		entry += truncateText(r.toSmartDashes(section.title), max(room, 1))
+		leader := max(r.width-textWidth(entry)-textWidth(label)-2, 1)
+		entries = append(entries, entry+" "+strings.Repeat("·", leader)+" "+label)
+	}
+	return entries
+}
+
+// Outline returns the heading tree of a Markdown document as text, listing
+// the ID of every heading for use with Config.SectionID. The section filters
+// of config apply as they do for Convert.
+func Outline(inp []byte, config Config) (string, error) {
+	doc, _, err := parseDocument(inp, &config)
+	if err != nil {
+		return "", err
+	}
+
+	r := NewUnicodeRenderer(config)
+	r.sections = outline(doc, inp)
+	entries := r.outlineEntries(0, func(s section) string {
+		return "#" + s.id
+	})
+	return strings.Join(entries, "\n"), nil
+}
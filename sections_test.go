@@ -0,0 +1,129 @@
+package unidoc
+
+import "testing"
+
+const sectionDocument = `# Guide
+
+Intro
+
+## Install
+
+Steps
+
+### Linux {#nix}
+
+apt
+
+## Usage
+
+Run it
+`
+
+func TestFilterSections(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter func(*Config)
+		want   string
+	}{
+		{"section", func(c *Config) { c.Section = " install " }, `
+▓▓ 𝗜𝗻𝘀𝘁𝗮𝗹𝗹
+──────────
+
+Steps
+
+▒▒▒ 𝗟𝗶𝗻𝘂𝘅
+
+apt`},
+		{"section id", func(c *Config) { c.SectionID = "nix" }, `
+▒▒▒ 𝗟𝗶𝗻𝘂𝘅
+
+apt`},
+		{"max level", func(c *Config) { c.MaxHeadingLevel = 2 }, `
+█ 𝗚𝘂𝗶𝗱𝗲
+═══════
+
+Intro
+
+▓▓ 𝗜𝗻𝘀𝘁𝗮𝗹𝗹
+──────────
+
+Steps
+
+▓▓ 𝗨𝘀𝗮𝗴𝗲
+────────
+
+Run it`},
+		{"section shifted", func(c *Config) { c.Section = "Install"; c.ShiftHeadings = -1 }, `
+█ 𝗜𝗻𝘀𝘁𝗮𝗹𝗹
+═════════
+
+Steps
+
+▓▓ 𝗟𝗶𝗻𝘂𝘅
+────────
+
+apt`},
+		{"shift beyond the levels", func(c *Config) { c.ShiftHeadings = 9 }, `
+▪ 𝗚𝘂𝗶𝗱𝗲
+
+Intro
+
+▪ 𝗜𝗻𝘀𝘁𝗮𝗹𝗹
+
+Steps
+
+▪ 𝗟𝗶𝗻𝘂𝘅
+
+apt
+
+▪ 𝗨𝘀𝗮𝗴𝗲
+
+Run it`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkOutput(t, convertTest(t, sectionDocument, tt.filter), tt.want)
+		})
+	}
+}
+
+func TestFilterSectionsNotFound(t *testing.T) {
+	for _, filter := range []func(*Config){
+		func(c *Config) { c.Section = "Nope" },
+		func(c *Config) { c.SectionID = "nope" },
+	} {
+		config := DefaultConfig()
+		filter(&config)
+		if _, err := Convert([]byte(sectionDocument), config); err == nil {
+			t.Errorf("Convert of section %q%q succeeded", config.Section, config.SectionID)
+		}
+	}
+}
+
+func TestOutline(t *testing.T) {
+	config := DefaultConfig()
+	config.Width = 30
+	got, err := Outline([]byte(sectionDocument), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkOutput(t, got, `
+█ Guide ··············· #guide
+  ▓▓ Install ········ #install
+    ▒▒▒ Linux ··········· #nix
+  ▓▓ Usage ············ #usage`)
+
+	config.MaxHeadingLevel = 2
+	got, err = Outline([]byte(sectionDocument), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkOutput(t, got, `
+█ Guide ··············· #guide
+  ▓▓ Install ········ #install
+  ▓▓ Usage ············ #usage`)
+
+	if got, err := Outline([]byte("text"), config); got != "" || err != nil {
+		t.Errorf("Outline without headings = %q, %v", got, err)
+	}
+}
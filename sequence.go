@@ -0,0 +1,493 @@
+package unidoc
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var (
+	// seqParticipantLine matches the declaration of a participant with an
+	// optional display name.
+	seqParticipantLine = regexp.MustCompile(`^(?:participant|actor)\s+(.+?)(?:\s+as\s+(.+))?$`)
+
+	// seqMessageLine matches a message and captures the sender, the arrow,
+	// the receiver and the text.
+	seqMessageLine = regexp.MustCompile(`^(.+?)\s*(-->>|->>|--x|-x|--\)|-\)|-->|->)\s*[+-]?\s*([^:]+?)\s*(?::(.*))?$`)
+
+	// seqNoteLine matches a note and captures its side, the participants and
+	// the text.
+	seqNoteLine = regexp.MustCompile(`^(?i:note)\s+(left of|right of|over)\s+([^:]+?)\s*:(.*)$`)
+
+	// seqFrameLine matches the start of a framed block, such as a loop.
+	seqFrameLine = regexp.MustCompile(`^(loop|alt|opt|par|critical|break)\b\s*(.*)$`)
+
+	// seqSectionLine matches the start of another section of a framed block.
+	seqSectionLine = regexp.MustCompile(`^(else|and|option)\b\s*(.*)$`)
+
+	// seqHiddenBlock matches the start of a block that is not drawn, but
+	// still closes with end.
+	seqHiddenBlock = regexp.MustCompile(`^(?:rect|box)\b`)
+
+	// seqIgnored matches statements that do not change the diagram.
+	seqIgnored = regexp.MustCompile(`^(?:activate|deactivate|title|create|destroy|links?|properties|details)\b`)
+
+	// seqBreaks turns the line breaks of mermaid into newlines.
+	seqBreaks = strings.NewReplacer("<br>", "\n", "<br/>", "\n", "<br />", "\n")
+)
+
+// seqStep is the kind of a step of a sequence diagram.
+type seqStep int
+
+const (
+	seqMessage seqStep = iota
+	seqNote
+	seqFrameStart
+	seqFrameSection
+	seqFrameEnd
+)
+
+// A seqParticipant is a participant of a sequence diagram with a lifeline.
+type seqParticipant struct {
+	name   string
+	center int // Column of the lifeline
+}
+
+// A seqFrame is a framed block of a sequence diagram, such as a loop.
+type seqFrame struct {
+	left, right int // Columns of the sides
+	top, bottom int // Rows of the top and bottom borders
+}
+
+// A seqEvent is a step of a sequence diagram.
+type seqEvent struct {
+	step     seqStep
+	from, to int    // Participants of a message, or the first and last under a note
+	text     string // Text of a message or note, or the label of a frame section
+	arrow    string // Arrow of a message, such as ->>
+	side     string // Side of a note: left of, right of or over
+	keyword  string // Keyword of a frame section, such as loop or else
+	frame    *seqFrame
+
+	lines       []string // Text wrapped for the layout
+	left, right int      // Columns covered by the step
+	row         int      // First row of the step
+}
+
+// A sequence is a sequence diagram parsed from the mermaid syntax.
+type sequence struct {
+	participants []*seqParticipant
+	ids          map[string]int
+	events       []*seqEvent
+}
+
+// parseSequence parses a sequence diagram from a subset of the mermaid
+// syntax: participants, messages such as A->>B: text or A-->>B: text, notes,
+// and loops and other framed blocks. The sequenceDiagram line may be left
+// out.
+func parseSequence(content string) (*sequence, error) {
+	s := &sequence{ids: make(map[string]int)}
+	var (
+		frames   []*seqFrame // Open blocks, nil for the blocks that are not drawn
+		numbered bool
+		number   int
+		first    = true
+	)
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "%%") {
+			continue
+		}
+		if first {
+			first = false
+			if line == "sequenceDiagram" {
+				continue
+			}
+		}
+
+		if line == "autonumber" {
+			numbered = true
+			continue
+		}
+		if line == "end" {
+			if len(frames) == 0 {
+				return nil, fmt.Errorf("end outside of a block")
+			}
+			if frame := frames[len(frames)-1]; frame != nil {
+				s.events = append(s.events, &seqEvent{step: seqFrameEnd, frame: frame})
+			}
+			frames = frames[:len(frames)-1]
+			continue
+		}
+		if seqHiddenBlock.MatchString(line) {
+			frames = append(frames, nil)
+			continue
+		}
+		if match := seqFrameLine.FindStringSubmatch(line); match != nil {
+			frame := &seqFrame{}
+			frames = append(frames, frame)
+			s.events = append(s.events, &seqEvent{step: seqFrameStart, keyword: match[1], text: match[2], frame: frame})
+			continue
+		}
+		if match := seqSectionLine.FindStringSubmatch(line); match != nil {
+			if len(frames) == 0 || frames[len(frames)-1] == nil {
+				return nil, fmt.Errorf("%s outside of a block", match[1])
+			}
+			s.events = append(s.events, &seqEvent{
+				step: seqFrameSection, keyword: match[1], text: match[2], frame: frames[len(frames)-1],
+			})
+			continue
+		}
+		if match := seqParticipantLine.FindStringSubmatch(line); match != nil {
+			i := s.participant(match[1])
+			if match[2] != "" {
+				s.participants[i].name = strings.ReplaceAll(seqBreaks.Replace(match[2]), "\n", " ")
+			}
+			continue
+		}
+		if match := seqNoteLine.FindStringSubmatch(line); match != nil {
+			var indexes []int
+			for _, id := range strings.Split(match[2], ",") {
+				indexes = append(indexes, s.participant(strings.TrimSpace(id)))
+			}
+			s.events = append(s.events, &seqEvent{
+				step: seqNote, side: strings.ToLower(match[1]),
+				from: slices.Min(indexes), to: slices.Max(indexes),
+				text: strings.TrimSpace(seqBreaks.Replace(match[3])),
+			})
+			continue
+		}
+		if match := seqMessageLine.FindStringSubmatch(line); match != nil {
+			text := strings.TrimSpace(seqBreaks.Replace(match[4]))
+			if numbered {
+				number++
+				text = strings.TrimSpace(strconv.Itoa(number) + ". " + text)
+			}
+			s.events = append(s.events, &seqEvent{
+				step: seqMessage, arrow: match[2], text: text,
+				from: s.participant(match[1]), to: s.participant(match[3]),
+			})
+			continue
+		}
+		if seqIgnored.MatchString(line) {
+			continue
+		}
+		return nil, fmt.Errorf("invalid statement: %s", line)
+	}
+
+	if len(frames) > 0 {
+		return nil, fmt.Errorf("block without end")
+	}
+	if len(s.participants) == 0 {
+		return nil, fmt.Errorf("no participants")
+	}
+	return s, nil
+}
+
+// participant returns the index of the participant with an ID, adding it if
+// there is none.
+func (s *sequence) participant(id string) int {
+	if i, ok := s.ids[id]; ok {
+		return i
+	}
+	s.ids[id] = len(s.participants)
+	s.participants = append(s.participants, &seqParticipant{name: id})
+	return len(s.participants) - 1
+}
+
+// frameLabel returns the label of a frame or one of its sections.
+func frameLabel(event *seqEvent) string {
+	return strings.TrimSpace(" " + toBoldSansSerifText(event.keyword) + " " + event.text)
+}
+
+// boxWidth returns the width of the box of a participant.
+func (p *seqParticipant) boxWidth() int {
+	return textWidth(p.name) + 4
+}
+
+// labelGap returns the participants whose lifelines the label of a message
+// goes between: the sender and the next participant towards the receiver, so
+// that the label of a long arrow crosses no other lifeline.
+func labelGap(event *seqEvent) (int, int) {
+	if event.to > event.from {
+		return event.from, event.from + 1
+	}
+	return event.from - 1, event.from
+}
+
+// layout wraps the texts of the diagram to limit columns, places the
+// lifelines as close as the texts between them allow and returns the width
+// of the diagram.
+func (s *sequence) layout(limit int) int {
+	textSize := func(event *seqEvent) int {
+		size := 0
+		for _, line := range event.lines {
+			size = max(size, textWidth(line))
+		}
+		return size
+	}
+
+	// Lifelines must be far enough apart for the boxes of the participants
+	// and the texts between them
+	n := len(s.participants)
+	gaps := make([]int, n-1)
+	for i := range gaps {
+		a, b := s.participants[i].boxWidth(), s.participants[i+1].boxWidth()
+		gaps[i] = a - a/2 + b/2 + 2
+	}
+	type span struct{ from, to, size int }
+	var spans []span
+	beside := func(i, size int, right bool) {
+		switch {
+		case right && i < n-1:
+			spans = append(spans, span{i, i + 1, size + 3})
+		case !right && i > 0:
+			spans = append(spans, span{i - 1, i, size + 3})
+		}
+	}
+	for _, event := range s.events {
+		event.lines = nil
+		if event.text != "" && (event.step == seqMessage || event.step == seqNote) {
+			event.lines = reflowText(event.text, limit)
+		}
+		size := textSize(event)
+		switch {
+		case event.step == seqMessage && event.from == event.to:
+			beside(event.from, max(3, size+1), true)
+		case event.step == seqMessage:
+			spans = append(spans, span{min(event.from, event.to), max(event.from, event.to), 4})
+			left, right := labelGap(event)
+			spans = append(spans, span{left, right, size + 4})
+		case event.step == seqNote && event.side == "right of":
+			beside(event.from, size+5, true)
+		case event.step == seqNote && event.side == "left of":
+			beside(event.from, size+5, false)
+		case event.step == seqNote && event.from < event.to:
+			spans = append(spans, span{event.from, event.to, size - 1})
+		case event.step == seqNote:
+			beside(event.from, (size+4)/2, false)
+			beside(event.from, size+4-(size+4)/2, true)
+		}
+	}
+	slices.SortStableFunc(spans, func(a, b span) int { return (a.to - a.from) - (b.to - b.from) })
+	for _, span := range spans {
+		missing := span.size - sumInts(gaps[span.from:span.to])
+		for i := span.from; missing > 0 && i < span.to; i++ {
+			extra := missing / (span.to - i)
+			gaps[i] += extra
+			missing -= extra
+		}
+	}
+
+	place := func(offset int) (int, int) {
+		center := offset
+		for i, p := range s.participants {
+			p.center = center
+			if i < len(gaps) {
+				center += gaps[i]
+			}
+		}
+
+		// Columns covered by the steps and the frames around them
+		first, last := math.MaxInt, math.MinInt
+		cover := func(left, right int) {
+			first, last = min(first, left), max(last, right)
+		}
+		for _, p := range s.participants {
+			cover(p.center-p.boxWidth()/2, p.center-p.boxWidth()/2+p.boxWidth()-1)
+		}
+		var frames []*seqFrame
+		extend := func(left, right int) {
+			if len(frames) > 0 {
+				frame := frames[len(frames)-1]
+				frame.left, frame.right = min(frame.left, left), max(frame.right, right)
+			}
+		}
+		for _, event := range s.events {
+			a, b := s.participants[event.from].center, s.participants[event.to].center
+			size := textSize(event)
+			switch event.step {
+			case seqMessage:
+				event.left, event.right = min(a, b), max(a, b)
+				if event.from == event.to {
+					event.right = a + max(3, size+1)
+				}
+			case seqNote:
+				switch event.side {
+				case "right of":
+					event.left, event.right = a+2, a+size+5
+				case "left of":
+					event.left, event.right = a-size-5, a-2
+				default:
+					mid, half := (a+b)/2, (size+4)/2
+					event.left, event.right = min(a-2, mid-half), max(b+2, mid-half+size+3)
+				}
+			case seqFrameStart:
+				event.frame.left, event.frame.right = math.MaxInt, math.MinInt
+				frames = append(frames, event.frame)
+				continue
+			case seqFrameSection:
+				continue
+			case seqFrameEnd:
+				frame := event.frame
+				frames = frames[:len(frames)-1]
+				if frame.left > frame.right {
+					// Empty blocks frame the first lifeline
+					frame.left, frame.right = s.participants[0].center, s.participants[0].center
+				}
+				frame.left, frame.right = frame.left-2, frame.right+2
+				for _, other := range s.events {
+					if other.frame == frame && other.step != seqFrameEnd {
+						frame.right = max(frame.right, frame.left+textWidth(frameLabel(other))+5)
+					}
+				}
+				// Sides next to a lifeline would run along it
+				for _, p := range s.participants {
+					if p.center >= frame.left && p.center <= frame.left+1 {
+						frame.left = p.center - 2
+					}
+					if p.center >= frame.right-1 && p.center <= frame.right {
+						frame.right = p.center + 2
+					}
+				}
+				event.left, event.right = frame.left, frame.right
+			}
+			extend(event.left, event.right)
+			cover(event.left, event.right)
+		}
+		return first, last
+	}
+	first, _ := place(0)
+	_, last := place(-first)
+	return last + 1
+}
+
+// seqHead returns the arrowhead of a message, or 0 for none.
+func seqHead(arrow string, right bool) rune {
+	heads := map[string][2]rune{">>": {'▶', '◀'}, "x": {'×', '×'}, ")": {'▷', '◁'}}
+	head, ok := heads[strings.TrimLeft(arrow, "-")]
+	switch {
+	case !ok:
+		return 0
+	case right:
+		return head[0]
+	default:
+		return head[1]
+	}
+}
+
+// draw lays out the diagram, wrapping the texts of messages and notes until
+// it fits into width, and draws it. It returns ErrUnprocessed if the diagram
+// does not fit.
+func (s *sequence) draw(width int) ([]string, error) {
+	limit := 1
+	for _, event := range s.events {
+		for _, line := range strings.Split(event.text, "\n") {
+			limit = max(limit, textWidth(line))
+		}
+	}
+	size := s.layout(limit)
+	for size > width {
+		if limit <= minWidth {
+			return nil, ErrUnprocessed
+		}
+		limit--
+		size = s.layout(limit)
+	}
+
+	// Rows of the steps, one apart
+	row := 4
+	for _, event := range s.events {
+		event.row = row
+		switch event.step {
+		case seqMessage:
+			row += len(event.lines) + 2
+			if event.from == event.to {
+				row += 2
+			}
+		case seqNote:
+			row += len(event.lines) + 3
+		case seqFrameStart:
+			event.frame.top = row
+			row++
+		case seqFrameSection:
+			row++
+		case seqFrameEnd:
+			event.frame.bottom = row
+			row += 2
+		}
+	}
+	c := newCanvas(size, row+3)
+
+	for _, p := range s.participants {
+		x, w := p.center-p.boxWidth()/2, p.boxWidth()
+		c.line(p.center, 2, p.center, row)
+		c.box(x, 0, w, 3, "┌┐└┘", p.name)
+		c.box(x, row, w, 3, "┌┐└┘", p.name)
+		c.set(p.center, 2, '┬')
+		c.set(p.center, row, '┴')
+	}
+
+	for _, event := range s.events {
+		a, b := s.participants[event.from].center, s.participants[event.to].center
+		switch event.step {
+		case seqMessage:
+			y := event.row + len(event.lines)
+			if event.from == event.to {
+				for i, line := range event.lines {
+					c.text(a+2, event.row+i, line, true)
+				}
+				c.path([][2]int{{a, y}, {a + 3, y}, {a + 3, y + 2}, {a, y + 2}})
+				if head := seqHead(event.arrow, false); head != 0 {
+					c.set(a+1, y+2, head)
+				}
+				continue
+			}
+
+			left, right := labelGap(event)
+			l, r := s.participants[left].center, s.participants[right].center
+			for i, line := range event.lines {
+				c.text(l+1+(r-l-1-textWidth(line))/2, event.row+i, line, true)
+			}
+			// The line stops short of the lifeline it points to
+			c.line(a, y, b-sign(b-a), y)
+			if strings.HasPrefix(event.arrow, "--") {
+				for x := event.left + 1; x < event.right; x++ {
+					if c.lines[y][x] == lineLeft|lineRight {
+						c.set(x, y, '╌')
+					}
+				}
+			}
+			if head := seqHead(event.arrow, b > a); head != 0 {
+				c.set(b-sign(b-a), y, head)
+			}
+		case seqNote:
+			c.box(event.left, event.row, event.right-event.left+1, len(event.lines)+2, "╭╮╰╯", "")
+			for i, line := range event.lines {
+				c.text(event.left+2, event.row+1+i, line, true)
+			}
+		case seqFrameEnd:
+			frame := event.frame
+			c.path([][2]int{
+				{frame.left, frame.top}, {frame.right, frame.top},
+				{frame.right, frame.bottom}, {frame.left, frame.bottom}, {frame.left, frame.top},
+			})
+		}
+	}
+
+	// Labels of frames and their sections go over the lines
+	for _, event := range s.events {
+		frame := event.frame
+		switch event.step {
+		case seqFrameSection:
+			c.line(frame.left, event.row, frame.right, event.row)
+			fallthrough
+		case seqFrameStart:
+			c.text(frame.left+2, event.row, " "+frameLabel(event)+" ", true)
+		}
+	}
+	return c.render(), nil
+}
@@ -0,0 +1,117 @@
+package unidoc
+
+import "testing"
+
+func TestSequence(t *testing.T) {
+	tests := []struct {
+		name, language, content string
+		want                    string
+	}{
+		{"messages", "mermaid", "sequenceDiagram\n  participant A as Alice\n  A->>B: Hello\n  B-->>A: Hi", `
+┌───────┐  ┌───┐
+│ Alice │  │ B │
+└───┬───┘  └─┬─┘
+    │        │
+    │ Hello  │
+    ├───────▶│
+    │        │
+    │   Hi   │
+    │◀╌╌╌╌╌╌╌┤
+    │        │
+┌───┴───┐  ┌─┴─┐
+│ Alice │  │ B │
+└───────┘  └───┘`},
+		{"numbers and notes", "sequence", "autonumber\nA->>B: ping\nNote right of B: thinks\nB-->>A: pong", `
+┌───┐      ┌───┐
+│ A │      │ B │
+└─┬─┘      └─┬─┘
+  │          │
+  │ 1. ping  │
+  ├─────────▶│
+  │          │
+  │          │ ╭────────╮
+  │          │ │ thinks │
+  │          │ ╰────────╯
+  │          │
+  │ 2. pong  │
+  │◀╌╌╌╌╌╌╌╌╌┤
+  │          │
+┌─┴─┐      ┌─┴─┐
+│ A │      │ B │
+└───┘      └───┘`},
+		{"loop", "sequence", "A->>B: go\nloop Every minute\n  B->>A: tick\nend", `
+┌───┐   ┌───┐
+│ A │   │ B │
+└─┬─┘   └─┬─┘
+  │       │
+  │  go   │
+  ├──────▶│
+  │       │
+╭─ 𝗹𝗼𝗼𝗽 Every minute ─╮
+│ │ tick  │           │
+│ │◀──────┤           │
+│ │       │           │
+╰─┼───────┼───────────╯
+  │       │
+┌─┴─┐   ┌─┴─┐
+│ A │   │ B │
+└───┘   └───┘`},
+		{"sections", "sequence", "alt ok\n  A->>B: yes\nelse fail\n  A->>B: no\nend", `
+┌───┐  ┌───┐
+│ A │  │ B │
+└─┬─┘  └─┬─┘
+  │      │
+╭─ 𝗮𝗹𝘁 ok ────╮
+│ │ yes  │    │
+│ ├─────▶│    │
+│ │      │    │
+├─ 𝗲𝗹𝘀𝗲 fail ─┤
+│ │  no  │    │
+│ ├─────▶│    │
+│ │      │    │
+╰─┼──────┼────╯
+  │      │
+┌─┴─┐  ┌─┴─┐
+│ A │  │ B │
+└───┘  └───┘`},
+		{"label longer than the gap", "sequence", "A->>B: x\nA->>C: a long request\nC-->>A: ok", `
+┌───┐             ┌───┐  ┌───┐
+│ A │             │ B │  │ C │
+└─┬─┘             └─┬─┘  └─┬─┘
+  │                 │      │
+  │        x        │      │
+  ├────────────────▶│      │
+  │                 │      │
+  │ a long request  │      │
+  ├─────────────────┼─────▶│
+  │                 │      │
+  │                 │  ok  │
+  │◀╌╌╌╌╌╌╌╌╌╌╌╌╌╌╌╌┼╌╌╌╌╌╌┤
+  │                 │      │
+┌─┴─┐             ┌─┴─┐  ┌─┴─┐
+│ A │             │ B │  │ C │
+└───┘             └───┘  └───┘`},
+		{"self message", "sequence", "A->>A: self", `
+┌───┐
+│ A │
+└─┬─┘
+  │
+  │ self
+  ├──╮
+  │  │
+  ├◀─╯
+  │
+┌─┴─┐
+│ A │
+└───┘`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := processTest(t, FencedBlock{Language: tt.language, Content: tt.content}, 40)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkOutput(t, got, tt.want)
+		})
+	}
+}
@@ -0,0 +1,304 @@
+package unidoc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// dataKind is the kind of a structured data value.
+type dataKind int
+
+const (
+	dataObject dataKind = iota
+	dataArray
+	dataString
+	dataNumber
+	dataBool
+	dataNull
+)
+
+// A dataValue is a value of JSON or YAML data. Objects keep the order of
+// their keys.
+type dataValue struct {
+	kind   dataKind
+	text   string       // Text of a scalar
+	keys   []string     // Keys of an object
+	values []*dataValue // Values of an object or the items of an array
+}
+
+// parseJSONValue decodes the next value from a JSON decoder.
+func parseJSONValue(dec *json.Decoder) (*dataValue, error) {
+	token, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch token := token.(type) {
+	case json.Delim:
+		value := &dataValue{kind: dataArray}
+		if token == '{' {
+			value.kind = dataObject
+		}
+		for dec.More() {
+			if value.kind == dataObject {
+				key, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				value.keys = append(value.keys, fmt.Sprint(key))
+			}
+			item, err := parseJSONValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			value.values = append(value.values, item)
+		}
+		_, err := dec.Token() // Closing delimiter
+		return value, err
+	case string:
+		return &dataValue{kind: dataString, text: token}, nil
+	case json.Number:
+		return &dataValue{kind: dataNumber, text: token.String()}, nil
+	case bool:
+		return &dataValue{kind: dataBool, text: strconv.FormatBool(token)}, nil
+	default:
+		return &dataValue{kind: dataNull, text: "null"}, nil
+	}
+}
+
+// parseJSON parses a JSON document.
+func parseJSON(content string) (*dataValue, error) {
+	dec := json.NewDecoder(strings.NewReader(content))
+	dec.UseNumber()
+	value, err := parseJSONValue(dec)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("unexpected data after the value")
+	}
+	return value, nil
+}
+
+// maxDataValues limits the values of YAML data, which aliases can multiply.
+const maxDataValues = 100_000
+
+// A yamlConverter converts YAML nodes, expanding aliases.
+type yamlConverter struct {
+	expanding map[*yaml.Node]bool // Anchors of the aliases being expanded
+	count     int                 // Values converted so far
+}
+
+// value converts a YAML node. Aliases that refer to a node containing them,
+// and data with too many values, are errors.
+func (c *yamlConverter) value(node *yaml.Node) (*dataValue, error) {
+	if c.count++; c.count > maxDataValues {
+		return nil, errors.New("too many values")
+	}
+
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return &dataValue{kind: dataNull, text: "null"}, nil
+		}
+		return c.value(node.Content[0])
+	case yaml.AliasNode:
+		if c.expanding[node.Alias] {
+			return nil, fmt.Errorf("recursive alias: %s", node.Value)
+		}
+		c.expanding[node.Alias] = true
+		defer delete(c.expanding, node.Alias)
+		return c.value(node.Alias)
+	case yaml.MappingNode:
+		value := &dataValue{kind: dataObject}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			item, err := c.value(node.Content[i+1])
+			if err != nil {
+				return nil, err
+			}
+			value.keys = append(value.keys, node.Content[i].Value)
+			value.values = append(value.values, item)
+		}
+		return value, nil
+	case yaml.SequenceNode:
+		value := &dataValue{kind: dataArray}
+		for _, child := range node.Content {
+			item, err := c.value(child)
+			if err != nil {
+				return nil, err
+			}
+			value.values = append(value.values, item)
+		}
+		return value, nil
+	}
+
+	switch node.ShortTag() {
+	case "!!int", "!!float":
+		return &dataValue{kind: dataNumber, text: node.Value}, nil
+	case "!!bool":
+		return &dataValue{kind: dataBool, text: node.Value}, nil
+	case "!!null":
+		return &dataValue{kind: dataNull, text: "null"}, nil
+	default:
+		return &dataValue{kind: dataString, text: node.Value}, nil
+	}
+}
+
+// parseYAML parses a YAML document.
+func parseYAML(content string) (*dataValue, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal([]byte(content), &node); err != nil {
+		return nil, err
+	}
+	converter := &yamlConverter{expanding: make(map[*yaml.Node]bool)}
+	return converter.value(&node)
+}
+
+// collapsedValue returns the summary of a container that is not expanded,
+// such as "[… 42 items]".
+func collapsedValue(value *dataValue) string {
+	count := len(value.values)
+	switch {
+	case value.kind == dataArray && count == 0:
+		return "[]"
+	case value.kind == dataArray && count == 1:
+		return "[… 1 item]"
+	case value.kind == dataArray:
+		return fmt.Sprintf("[… %d items]", count)
+	case count == 0:
+		return "{}"
+	case count == 1:
+		return "{… 1 key}"
+	default:
+		return fmt.Sprintf("{… %d keys}", count)
+	}
+}
+
+// scalarText returns a scalar styled by type: strings in quotes, booleans in
+// italics, null as ∅ and numbers as they are.
+func scalarText(value *dataValue) string {
+	switch value.kind {
+	case dataString:
+		return "“" + strings.ReplaceAll(value.text, "\n", "⏎") + "”"
+	case dataBool:
+		return toSlantedSansSerifText(value.text)
+	case dataNull:
+		return "∅"
+	default:
+		return value.text
+	}
+}
+
+// dataTreeOptions holds the layout options of a structured data tree.
+type dataTreeOptions struct {
+	depth int // Levels of containers to expand, 0 for all
+	items int // Longest array that is expanded
+}
+
+// drawDataTree lays out data as a tree with connectors. Keys are bold and
+// aligned so that the scalar values of an object line up. Containers nested
+// too deep to leave room for their keys are collapsed.
+func drawDataTree(root *dataValue, options dataTreeOptions, width int) []string {
+	expanded := func(value *dataValue, depth int) bool {
+		if value.kind != dataObject && value.kind != dataArray || len(value.values) == 0 {
+			return false
+		}
+		if options.depth > 0 && depth >= options.depth || 4*(depth+1) >= width {
+			return false
+		}
+		return value.kind != dataArray || len(value.values) <= options.items
+	}
+
+	var lines []string
+	var walk func(value *dataValue, prefix string, depth int)
+	walk = func(value *dataValue, prefix string, depth int) {
+		keys := value.keys
+		if value.kind == dataArray {
+			keys = make([]string, len(value.values))
+			for i := range keys {
+				keys[i] = "[" + strconv.Itoa(i) + "]"
+			}
+		}
+		keyWidth := 0
+		for _, key := range keys {
+			keyWidth = max(keyWidth, textWidth(key))
+		}
+
+		for i, item := range value.values {
+			connector, indent := "├── ", "│   "
+			if i == len(value.values)-1 {
+				connector, indent = "└── ", "    "
+			}
+			key := keys[i]
+			if value.kind == dataObject {
+				key = toBoldSansSerifText(key)
+			}
+
+			line := prefix + connector + key
+			switch {
+			case expanded(item, depth+1):
+				lines = append(lines, truncateText(line, width))
+				walk(item, prefix+indent, depth+1)
+				continue
+			case item.kind == dataObject || item.kind == dataArray:
+				line = padRight(line, textWidth(prefix+connector)+keyWidth) + "  " + collapsedValue(item)
+			default:
+				line = padRight(line, textWidth(prefix+connector)+keyWidth) + "  " + scalarText(item)
+			}
+			lines = append(lines, truncateText(line, width))
+		}
+	}
+
+	switch {
+	case expanded(root, 0):
+		walk(root, "", 0)
+	case root.kind == dataObject || root.kind == dataArray:
+		lines = append(lines, collapsedValue(root))
+	default:
+		lines = append(lines, truncateText(scalarText(root), width))
+	}
+	return lines
+}
+
+// structuredProcessor renders ```json and ```yaml blocks with the structured
+// class as data trees. The attributes depth and items limit the levels that
+// are expanded and the length of expanded arrays, 10 by default:
+//
+//	```json {.structured depth=2}
+//
+// Blocks without the class, or with data that does not parse, are left to
+// the boxed code rendering.
+type structuredProcessor struct {
+	parse func(content string) (*dataValue, error)
+}
+
+// ProcessBlock implements BlockProcessor.ProcessBlock.
+func (p structuredProcessor) ProcessBlock(block FencedBlock, width int) ([]string, error) {
+	if !slices.Contains(strings.Fields(block.Attributes["class"]), "structured") {
+		return nil, ErrUnprocessed
+	}
+
+	options := dataTreeOptions{items: 10}
+	for name, field := range map[string]*int{"depth": &options.depth, "items": &options.items} {
+		if text, ok := block.Attributes[name]; ok {
+			n, err := strconv.Atoi(text)
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("%w: invalid %s: %s", ErrUnprocessed, name, text)
+			}
+			*field = n
+		}
+	}
+
+	value, err := p.parse(block.Content)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnprocessed, err)
+	}
+	return drawDataTree(value, options, width), nil
+}
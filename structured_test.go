@@ -0,0 +1,84 @@
+package unidoc
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestStructuredProcessor(t *testing.T) {
+	tests := []struct {
+		name       string
+		block      FencedBlock
+		attributes map[string]string
+		width      int
+		want       string
+	}{
+		{"json", FencedBlock{Language: "json", Content: `{"name":"unidoc","tags":["a","b"],"nested":{"x":1.5,"ok":true,"none":null},"empty":[],"obj":{}}`}, nil, 40, `
+├── 𝗻𝗮𝗺𝗲    “unidoc”
+├── 𝘁𝗮𝗴𝘀
+│   ├── [0]  “a”
+│   └── [1]  “b”
+├── 𝗻𝗲𝘀𝘁𝗲𝗱
+│   ├── 𝘅     1.5
+│   ├── 𝗼𝗸    𝘵𝘳𝘶𝘦
+│   └── 𝗻𝗼𝗻𝗲  ∅
+├── 𝗲𝗺𝗽𝘁𝘆   []
+└── 𝗼𝗯𝗷     {}`},
+		{"yaml", FencedBlock{Language: "yaml", Content: "name: unidoc\nlist:\n  - 1\n  - two\nmap:\n  k: v\n"}, nil, 40, `
+├── 𝗻𝗮𝗺𝗲  “unidoc”
+├── 𝗹𝗶𝘀𝘁
+│   ├── [0]  1
+│   └── [1]  “two”
+└── 𝗺𝗮𝗽
+    └── 𝗸  “v”`},
+		{"yaml aliases", FencedBlock{Language: "yml", Content: "a: &x [1]\nb: *x\n"}, nil, 40, `
+├── 𝗮
+│   └── [0]  1
+└── 𝗯
+    └── [0]  1`},
+		{"items", FencedBlock{Language: "json", Content: `{"short":[1],"long":[1,2,3]}`}, map[string]string{"items": "2"}, 40, `
+├── 𝘀𝗵𝗼𝗿𝘁
+│   └── [0]  1
+└── 𝗹𝗼𝗻𝗴   [… 3 items]`},
+		{"depth", FencedBlock{Language: "json", Content: `{"a":{"b":{"c":1}},"d":{"e":1,"f":2}}`}, map[string]string{"depth": "1"}, 40, `
+├── 𝗮  {… 1 key}
+└── 𝗱  {… 2 keys}`},
+		{"scalar", FencedBlock{Language: "json", Content: `"just a string"`}, nil, 40, `
+“just a string”`},
+		{"empty yaml", FencedBlock{Language: "yaml", Content: ""}, nil, 40, `
+∅`},
+		{"truncated", FencedBlock{Language: "json", Content: `{"long":"a very long string value that will need to be truncated"}`}, nil, 30, `
+└── 𝗹𝗼𝗻𝗴  “a very long string…`},
+		{"nested past the width", FencedBlock{Language: "json", Content: strings.Repeat("[", 1000) + strings.Repeat("]", 1000)}, nil, 16, `
+└── [0]
+    └── [0]
+        └── [0]…`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.block.Attributes = map[string]string{"class": "structured"}
+			for name, value := range tt.attributes {
+				tt.block.Attributes[name] = value
+			}
+			got, err := processTest(t, tt.block, tt.width)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkOutput(t, got, tt.want)
+		})
+	}
+}
+
+func TestParseYAMLAliasBomb(t *testing.T) {
+	// Every level multiplies the values of the one before by nine
+	var b strings.Builder
+	b.WriteString("a0: &a0 [x, x, x, x, x, x, x, x, x]\n")
+	for i := 1; i < 9; i++ {
+		alias := fmt.Sprintf("*a%d", i-1)
+		fmt.Fprintf(&b, "a%d: &a%d [%s]\n", i, i, strings.Repeat(alias+", ", 8)+alias)
+	}
+	if _, err := parseYAML(b.String()); err == nil {
+		t.Error("parseYAML of an alias bomb succeeded")
+	}
+}
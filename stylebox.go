@@ -0,0 +1,78 @@
+package unidoc
+
+import (
+	"fmt"
+	"strings"
+)
+
+type BoxStyle int
+
+const (
+	BoxStyleSingle  BoxStyle = iota // Use single lines: ┌─┐
+	BoxStyleDouble                  // Use double lines: ╔═╗
+	BoxStyleRounded                 // Use single lines with rounded corners: ╭─╮
+	BoxStyleHeavy                   // Use heavy lines: ┏━┓
+)
+
+// boxChars holds the drawing characters of a box style.
+type boxChars struct {
+	topLeft, topRight, bottomLeft, bottomRight string
+	horizontal, vertical                       string
+}
+
+// chars returns the drawing characters for the box style.
+func (s BoxStyle) chars() boxChars {
+	switch s {
+	case BoxStyleDouble:
+		return boxChars{"╔", "╗", "╚", "╝", "═", "║"}
+	case BoxStyleRounded:
+		return boxChars{"╭", "╮", "╰", "╯", "─", "│"}
+	case BoxStyleHeavy:
+		return boxChars{"┏", "┓", "┗", "┛", "━", "┃"}
+	default:
+		return boxChars{"┌", "┐", "└", "┘", "─", "│"}
+	}
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface for BoxStyle.
+func (s *BoxStyle) UnmarshalText(text []byte) error {
+	switch strings.ToLower(string(text)) {
+	case "single":
+		*s = BoxStyleSingle
+	case "double":
+		*s = BoxStyleDouble
+	case "rounded":
+		*s = BoxStyleRounded
+	case "heavy":
+		*s = BoxStyleHeavy
+	default:
+		return fmt.Errorf("invalid box style: %s", text)
+	}
+	return nil
+}
+
+// Set implements the pflag.Value interface for BoxStyle.
+func (s *BoxStyle) Set(value string) error {
+	return s.UnmarshalText([]byte(value))
+}
+
+// String implements the pflag.Value interface for BoxStyle.
+func (s *BoxStyle) String() string {
+	switch *s {
+	case BoxStyleSingle:
+		return "single"
+	case BoxStyleDouble:
+		return "double"
+	case BoxStyleRounded:
+		return "rounded"
+	case BoxStyleHeavy:
+		return "heavy"
+	default:
+		return "unknown"
+	}
+}
+
+// Type implements the pflag.Value interface for BoxStyle.
+func (s *BoxStyle) Type() string {
+	return "boxStyle"
+}
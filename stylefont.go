@@ -0,0 +1,63 @@
+package unidoc
+
+import (
+	"fmt"
+	"strings"
+)
+
+type BannerFont int
+
+const (
+	BannerFontNone    BannerFont = iota // Use regular text
+	BannerFontBlock                     // Use 5×7 letters of half blocks: ▀▄█
+	BannerFontShadow                    // Use 5×7 letters of full blocks with a shadow: █░
+	BannerFontSmall                     // Use 3×5 letters of half blocks: ▀▄█
+	BannerFontBraille                   // Use 5×7 letters of braille dots: ⣿
+)
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface for BannerFont.
+func (s *BannerFont) UnmarshalText(text []byte) error {
+	switch strings.ToLower(string(text)) {
+	case "none":
+		*s = BannerFontNone
+	case "block":
+		*s = BannerFontBlock
+	case "shadow":
+		*s = BannerFontShadow
+	case "small":
+		*s = BannerFontSmall
+	case "braille":
+		*s = BannerFontBraille
+	default:
+		return fmt.Errorf("invalid banner font: %s", text)
+	}
+	return nil
+}
+
+// Set implements the pflag.Value interface for BannerFont.
+func (s *BannerFont) Set(value string) error {
+	return s.UnmarshalText([]byte(value))
+}
+
+// String implements the pflag.Value interface for BannerFont.
+func (s *BannerFont) String() string {
+	switch *s {
+	case BannerFontNone:
+		return "none"
+	case BannerFontBlock:
+		return "block"
+	case BannerFontShadow:
+		return "shadow"
+	case BannerFontSmall:
+		return "small"
+	case BannerFontBraille:
+		return "braille"
+	default:
+		return "unknown"
+	}
+}
+
+// Type implements the pflag.Value interface for BannerFont.
+func (s *BannerFont) Type() string {
+	return "bannerFont"
+}
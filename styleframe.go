@@ -0,0 +1,53 @@
+package unidoc
+
+import (
+	"fmt"
+	"strings"
+)
+
+type HeadingFrame int
+
+const (
+	HeadingFrameNone   HeadingFrame = iota // Use no frame around the heading
+	HeadingFrameBox                        // Use a double-lined box around the heading
+	HeadingFrameBanner                     // Use a centered heading between full-width rules
+)
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface for HeadingFrame.
+func (s *HeadingFrame) UnmarshalText(text []byte) error {
+	switch strings.ToLower(string(text)) {
+	case "none":
+		*s = HeadingFrameNone
+	case "box":
+		*s = HeadingFrameBox
+	case "banner":
+		*s = HeadingFrameBanner
+	default:
+		return fmt.Errorf("invalid heading frame: %s", text)
+	}
+	return nil
+}
+
+// Set implements the pflag.Value interface for HeadingFrame.
+func (s *HeadingFrame) Set(value string) error {
+	return s.UnmarshalText([]byte(value))
+}
+
+// String implements the pflag.Value interface for HeadingFrame.
+func (s *HeadingFrame) String() string {
+	switch *s {
+	case HeadingFrameNone:
+		return "none"
+	case HeadingFrameBox:
+		return "box"
+	case HeadingFrameBanner:
+		return "banner"
+	default:
+		return "unknown"
+	}
+}
+
+// Type implements the pflag.Value interface for HeadingFrame.
+func (s *HeadingFrame) Type() string {
+	return "headingFrame"
+}
@@ -0,0 +1,141 @@
+package unidoc
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// HeadingStyle configures how the headings of one level are rendered.
+type HeadingStyle struct {
+	Prefix          string       // Glyph in front of the heading text
+	Text            TextStyle    // Style for the heading text
+	Underline       string       // Character repeated below the heading, empty for none
+	UnderlineLength RuleLength   // Length of the underline
+	Frame           HeadingFrame // Frame around the heading
+	Font            BannerFont   // Bitmap font for large letters, none for text
+}
+
+// set sets an option of the heading style by name.
+func (s *HeadingStyle) set(name, value string) error {
+	switch name {
+	case "prefix":
+		s.Prefix = value
+	case "text":
+		return s.Text.UnmarshalText([]byte(value))
+	case "underline":
+		s.Underline = value
+	case "length":
+		return s.UnderlineLength.UnmarshalText([]byte(value))
+	case "frame":
+		return s.Frame.UnmarshalText([]byte(value))
+	case "font":
+		return s.Font.UnmarshalText([]byte(value))
+	default:
+		return fmt.Errorf("unknown heading option: %s", name)
+	}
+	return nil
+}
+
+// HeadingStyles holds the heading styles by level, starting with level 1.
+// Levels beyond the last style use the last style, and no styles at all
+// stand for the styles of DefaultConfig.
+type HeadingStyles []HeadingStyle
+
+// level returns the style for headings of a level.
+func (s HeadingStyles) level(level int) HeadingStyle {
+	if len(s) == 0 {
+		s = DefaultConfig().Headings
+	}
+	return s[min(level, len(s))-1]
+}
+
+// setOption sets an option of the style for a level, on a copy of the
+// styles.
+func (s *HeadingStyles) setOption(level int, name, value string) error {
+	if level < 1 || level > 6 {
+		return fmt.Errorf("invalid heading level: %d", level)
+	}
+
+	styles := slices.Clone(*s)
+	for len(styles) < level {
+		styles = append(styles, styles.level(len(styles)+1))
+	}
+	if err := styles[level-1].set(name, value); err != nil {
+		return err
+	}
+	*s = styles
+	return nil
+}
+
+// setOptions sets the options for a level from "name=value,name=value".
+func (s *HeadingStyles) setOptions(level int, options string) error {
+	for _, option := range strings.Split(options, ",") {
+		name, value, _ := strings.Cut(option, "=")
+		if err := s.setOption(level, strings.TrimSpace(name), value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setOptionMap sets the options of levels from a front matter value that
+// maps levels to either "name=value,name=value" text or a map of options.
+func (s *HeadingStyles) setOptionMap(value any) error {
+	levels, err := optionMap(value)
+	if err != nil {
+		return err
+	}
+
+	for _, levelText := range slices.Sorted(maps.Keys(levels)) {
+		level, err := strconv.Atoi(levelText)
+		if err != nil {
+			return fmt.Errorf("invalid heading level: %s", levelText)
+		}
+
+		if options, err := optionMap(levels[levelText]); err == nil {
+			for _, name := range slices.Sorted(maps.Keys(options)) {
+				text, err := optionText(options[name])
+				if err != nil {
+					return err
+				}
+				if err := s.setOption(level, name, text); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		options, err := optionText(levels[levelText])
+		if err != nil {
+			return err
+		}
+		if err := s.setOptions(level, options); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Set implements the pflag.Value interface for HeadingStyles. The value has
+// the form "LEVEL:name=value,name=value".
+func (s *HeadingStyles) Set(value string) error {
+	levelText, options, ok := strings.Cut(value, ":")
+	level, err := strconv.Atoi(levelText)
+	if !ok || err != nil {
+		return fmt.Errorf("invalid heading style: %s", value)
+	}
+	return s.setOptions(level, options)
+}
+
+// String implements the pflag.Value interface for HeadingStyles.
+func (s *HeadingStyles) String() string {
+	return ""
+}
+
+// Type implements the pflag.Value interface for HeadingStyles.
+func (s *HeadingStyles) Type() string {
+	return "headingStyle"
+}
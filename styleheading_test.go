@@ -0,0 +1,157 @@
+package unidoc
+
+import (
+	"strings"
+	"testing"
+)
+
+const headingDocument = "# Title here\n\n## Sub title\n\n### Third\n\n#### Fourth"
+
+func TestHeadingStyles(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []string
+		want    string
+	}{
+		{"default", nil, `
+█ 𝗧𝗶𝘁𝗹𝗲 𝗵𝗲𝗿𝗲
+════════════
+
+▓▓ 𝗦𝘂𝗯 𝘁𝗶𝘁𝗹𝗲
+────────────
+
+▒▒▒ 𝗧𝗵𝗶𝗿𝗱
+
+░░░░ 𝗙𝗼𝘂𝗿𝘁𝗵`},
+		{"box", []string{"1:frame=box,prefix=", "1:text=plain"}, `
+╔════════════╗
+║ Title here ║
+╚════════════╝
+
+▓▓ 𝗦𝘂𝗯 𝘁𝗶𝘁𝗹𝗲
+────────────
+
+▒▒▒ 𝗧𝗵𝗶𝗿𝗱
+
+░░░░ 𝗙𝗼𝘂𝗿𝘁𝗵`},
+		{"banner", []string{"1:frame=banner,underline="}, `
+══════════════════════════════
+         █ 𝗧𝗶𝘁𝗹𝗲 𝗵𝗲𝗿𝗲
+══════════════════════════════
+
+▓▓ 𝗦𝘂𝗯 𝘁𝗶𝘁𝗹𝗲
+────────────
+
+▒▒▒ 𝗧𝗵𝗶𝗿𝗱
+
+░░░░ 𝗙𝗼𝘂𝗿𝘁𝗵`},
+		{"underline", []string{"2:text=script,underline=~,length=full"}, `
+█ 𝗧𝗶𝘁𝗹𝗲 𝗵𝗲𝗿𝗲
+════════════
+
+▓▓ 𝒮𝓊𝒷 𝓉𝒾𝓉𝓁ℯ
+~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+
+▒▒▒ 𝗧𝗵𝗶𝗿𝗱
+
+░░░░ 𝗙𝗼𝘂𝗿𝘁𝗵`},
+		{"text styles", []string{"1:text=small-caps", "3:text=uppercase,prefix=§", "4:text=double-struck"}, `
+█ Tɪᴛʟᴇ ʜᴇʀᴇ
+════════════
+
+▓▓ 𝗦𝘂𝗯 𝘁𝗶𝘁𝗹𝗲
+────────────
+
+§ THIRD
+
+░░░░ 𝔽𝕠𝕦𝕣𝕥𝕙`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := convertTest(t, headingDocument, func(c *Config) {
+				for _, option := range tt.options {
+					if err := c.Headings.Set(option); err != nil {
+						t.Fatal(err)
+					}
+				}
+			})
+			checkOutput(t, got, tt.want)
+		})
+	}
+}
+
+func TestHeadingBoxWrap(t *testing.T) {
+	got := convertTest(t, "# A heading far too long to fit into a box of thirty columns", func(c *Config) {
+		if err := c.Headings.Set("1:frame=box,prefix="); err != nil {
+			t.Fatal(err)
+		}
+	})
+	checkOutput(t, got, `
+╔═══════════════════════════╗
+║ 𝗔 𝗵𝗲𝗮𝗱𝗶𝗻𝗴 𝗳𝗮𝗿 𝘁𝗼𝗼 𝗹𝗼𝗻𝗴 𝘁𝗼 ║
+║ 𝗳𝗶𝘁 𝗶𝗻𝘁𝗼 𝗮 𝗯𝗼𝘅 𝗼𝗳 𝘁𝗵𝗶𝗿𝘁𝘆  ║
+║ 𝗰𝗼𝗹𝘂𝗺𝗻𝘀                   ║
+╚═══════════════════════════╝`)
+}
+
+func TestHeadingStylesFrontMatter(t *testing.T) {
+	got := convertTest(t, `---
+unidoc:
+  headings:
+    1: {frame: box, prefix: "", text: plain}
+    2: "prefix=▶,underline="
+---
+# One
+
+## Two`)
+	checkOutput(t, got, `
+╔═════╗
+║ One ║
+╚═════╝
+
+▶ 𝗧𝘄𝗼`)
+}
+
+func TestHeadingStylesSet(t *testing.T) {
+	tests := []struct {
+		value, err string
+	}{
+		{"x:prefix=a", "invalid heading style"},
+		{"1", "invalid heading style"},
+		{"0:prefix=", "invalid heading level: 0"},
+		{"7:prefix=a", "invalid heading level: 7"},
+		{"1:colour=red", "unknown heading option: colour"},
+		{"1:text=wavy", "invalid text style: wavy"},
+		{"1:frame=circle", "circle"},
+		{"1:font=gothic", "invalid banner font: gothic"},
+	}
+	for _, tt := range tests {
+		var styles HeadingStyles
+		if err := styles.Set(tt.value); err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("Set(%q) = %v, want %q", tt.value, err, tt.err)
+		}
+	}
+}
+
+func TestHeadingStylesCopy(t *testing.T) {
+	styles := DefaultConfig().Headings
+	copied := styles
+	if err := copied.Set("1:prefix=#"); err != nil {
+		t.Fatal(err)
+	}
+	if styles[0].Prefix != "█" || copied[0].Prefix != "#" {
+		t.Errorf("prefixes %q and %q, want █ and #", styles[0].Prefix, copied[0].Prefix)
+	}
+
+	// Levels beyond the styles start from the last one
+	if err := copied.Set("8:prefix=x"); err == nil {
+		t.Error("Set of level 8 succeeded")
+	}
+	short := HeadingStyles{{Prefix: "*"}}
+	if err := short.Set("3:underline=-"); err != nil {
+		t.Fatal(err)
+	}
+	if len(short) != 3 || short[2].Prefix != "*" || short[2].Underline != "-" {
+		t.Errorf("styles = %+v, want three levels with the prefix of the first", short)
+	}
+}
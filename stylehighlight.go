@@ -0,0 +1,76 @@
+package unidoc
+
+import (
+	"fmt"
+	"strings"
+)
+
+type HighlightStyle int
+
+const (
+	HighlightStylePlain           HighlightStyle = iota // Use plain style for highlighted text
+	HighlightStyleMarkers                               // Use simple markers for highlighted text
+	HighlightStyleUnderline                             // Use a combining low line under highlighted text
+	HighlightStyleDoubleUnderline                       // Use a combining double low line under highlighted text
+	HighlightStyleBrackets                              // Use lenticular brackets around highlighted text
+)
+
+// combiningMark returns the combining character placed after every character
+// of the text, or 0 if the style does not use one.
+func (s HighlightStyle) combiningMark() rune {
+	switch s {
+	case HighlightStyleUnderline:
+		return '\u0332' // Combining low line
+	case HighlightStyleDoubleUnderline:
+		return '\u0333' // Combining double low line
+	default:
+		return 0
+	}
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface for HighlightStyle.
+func (s *HighlightStyle) UnmarshalText(text []byte) error {
+	switch strings.ToLower(string(text)) {
+	case "plain":
+		*s = HighlightStylePlain
+	case "markers":
+		*s = HighlightStyleMarkers
+	case "underline":
+		*s = HighlightStyleUnderline
+	case "double-underline":
+		*s = HighlightStyleDoubleUnderline
+	case "brackets":
+		*s = HighlightStyleBrackets
+	default:
+		return fmt.Errorf("invalid highlight style: %s", text)
+	}
+	return nil
+}
+
+// Set implements the pflag.Value interface for HighlightStyle.
+func (s *HighlightStyle) Set(value string) error {
+	return s.UnmarshalText([]byte(value))
+}
+
+// String implements the pflag.Value interface for HighlightStyle.
+func (s *HighlightStyle) String() string {
+	switch *s {
+	case HighlightStylePlain:
+		return "plain"
+	case HighlightStyleMarkers:
+		return "markers"
+	case HighlightStyleUnderline:
+		return "underline"
+	case HighlightStyleDoubleUnderline:
+		return "double-underline"
+	case HighlightStyleBrackets:
+		return "brackets"
+	default:
+		return "unknown"
+	}
+}
+
+// Type implements the pflag.Value interface for HighlightStyle.
+func (s *HighlightStyle) Type() string {
+	return "highlightStyle"
+}
@@ -0,0 +1,58 @@
+package unidoc
+
+import (
+	"fmt"
+	"strings"
+)
+
+type ImageStyle int
+
+const (
+	ImageStyleText      ImageStyle = iota // Use the alt text and URL: 🖼️  alt <url>
+	ImageStyleBlocks                      // Draw local images with half blocks, 1×2 pixels per cell
+	ImageStyleQuadrants                   // Draw local images with quadrant blocks, 2×2 pixels per cell
+	ImageStyleBraille                     // Draw local images with braille patterns, 2×4 pixels per cell
+)
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface for ImageStyle.
+func (s *ImageStyle) UnmarshalText(text []byte) error {
+	switch strings.ToLower(string(text)) {
+	case "text":
+		*s = ImageStyleText
+	case "blocks":
+		*s = ImageStyleBlocks
+	case "quadrants":
+		*s = ImageStyleQuadrants
+	case "braille":
+		*s = ImageStyleBraille
+	default:
+		return fmt.Errorf("invalid image style: %s", text)
+	}
+	return nil
+}
+
+// Set implements the pflag.Value interface for ImageStyle.
+func (s *ImageStyle) Set(value string) error {
+	return s.UnmarshalText([]byte(value))
+}
+
+// String implements the pflag.Value interface for ImageStyle.
+func (s *ImageStyle) String() string {
+	switch *s {
+	case ImageStyleText:
+		return "text"
+	case ImageStyleBlocks:
+		return "blocks"
+	case ImageStyleQuadrants:
+		return "quadrants"
+	case ImageStyleBraille:
+		return "braille"
+	default:
+		return "unknown"
+	}
+}
+
+// Type implements the pflag.Value interface for ImageStyle.
+func (s *ImageStyle) Type() string {
+	return "imageStyle"
+}
@@ -0,0 +1,63 @@
+package unidoc
+
+import (
+	"fmt"
+	"strings"
+)
+
+type LinkStyle int
+
+const (
+	LinkStyleEmoji    LinkStyle = iota // Use brackets and a link emoji: [text] 🔗 <url>
+	LinkStyleInline                    // Use the URL after the text: text <url>
+	LinkStyleText                      // Use the link text only
+	LinkStyleFootnote                  // Use numbered references listed at the end of the document
+	LinkStyleQR                        // Use numbered references listed at the end with QR codes
+)
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface for LinkStyle.
+func (s *LinkStyle) UnmarshalText(text []byte) error {
+	switch strings.ToLower(string(text)) {
+	case "emoji":
+		*s = LinkStyleEmoji
+	case "inline":
+		*s = LinkStyleInline
+	case "text":
+		*s = LinkStyleText
+	case "footnote":
+		*s = LinkStyleFootnote
+	case "qr":
+		*s = LinkStyleQR
+	default:
+		return fmt.Errorf("invalid link style: %s", text)
+	}
+	return nil
+}
+
+// Set implements the pflag.Value interface for LinkStyle.
+func (s *LinkStyle) Set(value string) error {
+	return s.UnmarshalText([]byte(value))
+}
+
+// String implements the pflag.Value interface for LinkStyle.
+func (s *LinkStyle) String() string {
+	switch *s {
+	case LinkStyleEmoji:
+		return "emoji"
+	case LinkStyleInline:
+		return "inline"
+	case LinkStyleText:
+		return "text"
+	case LinkStyleFootnote:
+		return "footnote"
+	case LinkStyleQR:
+		return "qr"
+	default:
+		return "unknown"
+	}
+}
+
+// Type implements the pflag.Value interface for LinkStyle.
+func (s *LinkStyle) Type() string {
+	return "linkStyle"
+}
@@ -0,0 +1,73 @@
+package unidoc
+
+import (
+	"fmt"
+	"strings"
+)
+
+type NumberStyle int
+
+const (
+	NumberStyleAngle         NumberStyle = iota // Use numbers in angle brackets: ⟨1⟩ ⟨2⟩ ⟨3⟩
+	NumberStyleDecimal                          // Use plain numbers: 1. 2. 3.
+	NumberStyleCircled                          // Use circled numbers: ① ② ③
+	NumberStyleParenthesized                    // Use parenthesized numbers: ⑴ ⑵ ⑶
+	NumberStyleCircledLetter                    // Use circled letters: 🅐 🅑 🅒
+	NumberStyleRoman                            // Use lowercase Roman numerals: ⅰ ⅱ ⅲ
+	NumberStyleUpperRoman                       // Use uppercase Roman numerals: Ⅰ Ⅱ Ⅲ
+)
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface for NumberStyle.
+func (s *NumberStyle) UnmarshalText(text []byte) error {
+	switch strings.ToLower(string(text)) {
+	case "angle":
+		*s = NumberStyleAngle
+	case "decimal":
+		*s = NumberStyleDecimal
+	case "circled":
+		*s = NumberStyleCircled
+	case "parenthesized":
+		*s = NumberStyleParenthesized
+	case "circled-letter":
+		*s = NumberStyleCircledLetter
+	case "roman":
+		*s = NumberStyleRoman
+	case "upper-roman":
+		*s = NumberStyleUpperRoman
+	default:
+		return fmt.Errorf("invalid number style: %s", text)
+	}
+	return nil
+}
+
+// Set implements the pflag.Value interface for NumberStyle.
+func (s *NumberStyle) Set(value string) error {
+	return s.UnmarshalText([]byte(value))
+}
+
+// String implements the pflag.Value interface for NumberStyle.
+func (s *NumberStyle) String() string {
+	switch *s {
+	case NumberStyleAngle:
+		return "angle"
+	case NumberStyleDecimal:
+		return "decimal"
+	case NumberStyleCircled:
+		return "circled"
+	case NumberStyleParenthesized:
+		return "parenthesized"
+	case NumberStyleCircledLetter:
+		return "circled-letter"
+	case NumberStyleRoman:
+		return "roman"
+	case NumberStyleUpperRoman:
+		return "upper-roman"
+	default:
+		return "unknown"
+	}
+}
+
+// Type implements the pflag.Value interface for NumberStyle.
+func (s *NumberStyle) Type() string {
+	return "numberStyle"
+}
@@ -0,0 +1,53 @@
+package unidoc
+
+import (
+	"fmt"
+	"strings"
+)
+
+type RuleLength int
+
+const (
+	RuleLengthText RuleLength = iota // Draw the rule as wide as the text above it
+	RuleLengthFull                   // Draw the rule across the full width
+	RuleLengthNone                   // Draw no rule
+)
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface for RuleLength.
+func (s *RuleLength) UnmarshalText(text []byte) error {
+	switch strings.ToLower(string(text)) {
+	case "text":
+		*s = RuleLengthText
+	case "full":
+		*s = RuleLengthFull
+	case "none":
+		*s = RuleLengthNone
+	default:
+		return fmt.Errorf("invalid rule length: %s", text)
+	}
+	return nil
+}
+
+// Set implements the pflag.Value interface for RuleLength.
+func (s *RuleLength) Set(value string) error {
+	return s.UnmarshalText([]byte(value))
+}
+
+// String implements the pflag.Value interface for RuleLength.
+func (s *RuleLength) String() string {
+	switch *s {
+	case RuleLengthText:
+		return "text"
+	case RuleLengthFull:
+		return "full"
+	case RuleLengthNone:
+		return "none"
+	default:
+		return "unknown"
+	}
+}
+
+// Type implements the pflag.Value interface for RuleLength.
+func (s *RuleLength) Type() string {
+	return "ruleLength"
+}
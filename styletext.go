@@ -0,0 +1,93 @@
+package unidoc
+
+import (
+	"fmt"
+	"strings"
+)
+
+type TextStyle int
+
+const (
+	TextStylePlain            TextStyle = iota // Use plain text
+	TextStyleBoldSansSerif                     // Use mathematical bold sans-serif: 𝗧𝗲𝘅𝘁
+	TextStyleSlantedSansSerif                  // Use mathematical sans-serif italic: 𝘛𝘦𝘹𝘵
+	TextStyleScript                            // Use mathematical script: 𝒯ℯ𝓍𝓉
+	TextStyleDoubleStruck                      // Use mathematical double-struck: 𝕋𝕖𝕩𝕥
+	TextStyleUppercase                         // Use uppercase letters: TEXT
+	TextStyleSmallCaps                         // Use small capitals: Tᴇxᴛ
+)
+
+// apply converts text to the style.
+func (s TextStyle) apply(text string) string {
+	switch s {
+	case TextStyleBoldSansSerif:
+		return toBoldSansSerifText(text)
+	case TextStyleSlantedSansSerif:
+		return toSlantedSansSerifText(text)
+	case TextStyleScript:
+		return toItalicScriptText(text)
+	case TextStyleDoubleStruck:
+		return toDoubleStruckText(text)
+	case TextStyleUppercase:
+		return strings.ToUpper(text)
+	case TextStyleSmallCaps:
+		return toSmallCapsText(text)
+	default:
+		return text
+	}
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface for TextStyle.
+func (s *TextStyle) UnmarshalText(text []byte) error {
+	switch strings.ToLower(string(text)) {
+	case "plain":
+		*s = TextStylePlain
+	case "bold-sans-serif":
+		*s = TextStyleBoldSansSerif
+	case "slanted-sans-serif":
+		*s = TextStyleSlantedSansSerif
+	case "script":
+		*s = TextStyleScript
+	case "double-struck":
+		*s = TextStyleDoubleStruck
+	case "uppercase":
+		*s = TextStyleUppercase
+	case "small-caps":
+		*s = TextStyleSmallCaps
+	default:
+		return fmt.Errorf("invalid text style: %s", text)
+	}
+	return nil
+}
+
+// Set implements the pflag.Value interface for TextStyle.
+func (s *TextStyle) Set(value string) error {
+	return s.UnmarshalText([]byte(value))
+}
+
+// String implements the pflag.Value interface for TextStyle.
+func (s *TextStyle) String() string {
+	switch *s {
+	case TextStylePlain:
+		return "plain"
+	case TextStyleBoldSansSerif:
+		return "bold-sans-serif"
+	case TextStyleSlantedSansSerif:
+		return "slanted-sans-serif"
+	case TextStyleScript:
+		return "script"
+	case TextStyleDoubleStruck:
+		return "double-struck"
+	case TextStyleUppercase:
+		return "uppercase"
+	case TextStyleSmallCaps:
+		return "small-caps"
+	default:
+		return "unknown"
+	}
+}
+
+// Type implements the pflag.Value interface for TextStyle.
+func (s *TextStyle) Type() string {
+	return "textStyle"
+}
@@ -0,0 +1,53 @@
+package unidoc
+
+import (
+	"fmt"
+	"strings"
+)
+
+type TitleStyle int
+
+const (
+	TitleStylePlain  TitleStyle = iota // Use plain lines for the title block
+	TitleStyleBanner                   // Use heavy rules above and below the title block
+	TitleStyleBox                      // Use a double-lined box around the title
+)
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface for TitleStyle.
+func (s *TitleStyle) UnmarshalText(text []byte) error {
+	switch strings.ToLower(string(text)) {
+	case "plain":
+		*s = TitleStylePlain
+	case "banner":
+		*s = TitleStyleBanner
+	case "box":
+		*s = TitleStyleBox
+	default:
+		return fmt.Errorf("invalid title style: %s", text)
+	}
+	return nil
+}
+
+// Set implements the pflag.Value interface for TitleStyle.
+func (s *TitleStyle) Set(value string) error {
+	return s.UnmarshalText([]byte(value))
+}
+
+// String implements the pflag.Value interface for TitleStyle.
+func (s *TitleStyle) String() string {
+	switch *s {
+	case TitleStylePlain:
+		return "plain"
+	case TitleStyleBanner:
+		return "banner"
+	case TitleStyleBox:
+		return "box"
+	default:
+		return "unknown"
+	}
+}
+
+// Type implements the pflag.Value interface for TitleStyle.
+func (s *TitleStyle) Type() string {
+	return "titleStyle"
+}
@@ -0,0 +1,76 @@
+package unidoc
+
+import (
+	"fmt"
+	"strings"
+)
+
+type UnderlineStyle int
+
+const (
+	UnderlineStylePlain           UnderlineStyle = iota // Use plain style for underlined text
+	UnderlineStyleMarkers                               // Use simple markers for underlined text
+	UnderlineStyleUnderline                             // Use a combining low line under underlined text
+	UnderlineStyleDoubleUnderline                       // Use a combining double low line under underlined text
+	UnderlineStyleBrackets                              // Use lenticular brackets around underlined text
+)
+
+// combiningMark returns the combining character placed after every character
+// of the text, or 0 if the style does not use one.
+func (s UnderlineStyle) combiningMark() rune {
+	switch s {
+	case UnderlineStyleUnderline:
+		return '\u0332' // Combining low line
+	case UnderlineStyleDoubleUnderline:
+		return '\u0333' // Combining double low line
+	default:
+		return 0
+	}
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface for UnderlineStyle.
+func (s *UnderlineStyle) UnmarshalText(text []byte) error {
+	switch strings.ToLower(string(text)) {
+	case "plain":
+		*s = UnderlineStylePlain
+	case "markers":
+		*s = UnderlineStyleMarkers
+	case "underline":
+		*s = UnderlineStyleUnderline
+	case "double-underline":
+		*s = UnderlineStyleDoubleUnderline
+	case "brackets":
+		*s = UnderlineStyleBrackets
+	default:
+		return fmt.Errorf("invalid underline style: %s", text)
+	}
+	return nil
+}
+
+// Set implements the pflag.Value interface for UnderlineStyle.
+func (s *UnderlineStyle) Set(value string) error {
+	return s.UnmarshalText([]byte(value))
+}
+
+// String implements the pflag.Value interface for UnderlineStyle.
+func (s *UnderlineStyle) String() string {
+	switch *s {
+	case UnderlineStylePlain:
+		return "plain"
+	case UnderlineStyleMarkers:
+		return "markers"
+	case UnderlineStyleUnderline:
+		return "underline"
+	case UnderlineStyleDoubleUnderline:
+		return "double-underline"
+	case UnderlineStyleBrackets:
+		return "brackets"
+	default:
+		return "unknown"
+	}
+}
+
+// Type implements the pflag.Value interface for UnderlineStyle.
+func (s *UnderlineStyle) Type() string {
+	return "underlineStyle"
+}
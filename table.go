@@ -0,0 +1,127 @@
+package unidoc
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// numericCell matches cells holding a number, such as "-1,024.5", "12%" or
+// "$3.50", which are aligned to the right.
+var numericCell = regexp.MustCompile(`^[-+]?[$€£¥]?[0-9][0-9,_]*(\.[0-9]+)?([eE][-+]?[0-9]+)?%?$`)
+
+// tableColumnWidths returns the widths of the columns of a table with the
+// given natural widths fitted into the space available for cell content.
+// The widest column gives up a column at a time until the table fits, first
+// down to the width of its longest word and then further.
+func tableColumnWidths(natural, words []int, available int) []int {
+	widths := slices.Clone(natural)
+	for _, floor := range [][]int{words, nil} {
+		for total := sumInts(widths); total > available; total-- {
+			widest := -1
+			for i, w := range widths {
+				if (widest < 0 || w > widths[widest]) && (floor == nil || w > floor[i]) {
+					widest = i
+				}
+			}
+			if widest < 0 || widths[widest] <= 1 {
+				break
+			}
+			widths[widest]--
+		}
+	}
+	return widths
+}
+
+// sumInts returns the sum of numbers.
+func sumInts(numbers []int) int {
+	sum := 0
+	for _, n := range numbers {
+		sum += n
+	}
+	return sum
+}
+
+// drawTable lays out rows as a box-drawn table no wider than width. The first
+// row is set in bold as the header if header is set, columns holding only
+// numbers are aligned to the right, and cells wrap to fit their column.
+func drawTable(rows [][]string, header bool, width int) []string {
+	columns := 0
+	for _, row := range rows {
+		columns = max(columns, len(row))
+	}
+	if columns == 0 {
+		return nil
+	}
+
+	// Line breaks and tabs in quoted fields run on as spaces
+	cell := func(row []string, c int) string {
+		if c >= len(row) {
+			return ""
+		}
+		return strings.Join(strings.Fields(row[c]), " ")
+	}
+
+	natural := make([]int, columns)
+	words := make([]int, columns)
+	numeric := make([]bool, columns)
+	for i := range numeric {
+		numeric[i] = true
+	}
+	for r, row := range rows {
+		for c := range columns {
+			cell := cell(row, c)
+			natural[c] = max(natural[c], textWidth(cell))
+			for _, word := range strings.Fields(cell) {
+				words[c] = max(words[c], textWidth(word))
+			}
+			if (r > 0 || !header) && cell != "" && !numericCell.MatchString(cell) {
+				numeric[c] = false
+			}
+		}
+	}
+	widths := tableColumnWidths(natural, words, width-3*columns-1)
+
+	rule := func(left, middle, right string) string {
+		parts := make([]string, columns)
+		for c, w := range widths {
+			parts[c] = strings.Repeat("─", w+2)
+		}
+		return left + strings.Join(parts, middle) + right
+	}
+
+	lines := []string{rule("┌", "┬", "┐")}
+	for r, row := range rows {
+		// Wrap the cells of the row into lines of their column
+		cells := make([][]string, columns)
+		height := 1
+		for c := range columns {
+			cells[c] = wrapLine(cell(row, c), widths[c])
+			height = max(height, len(cells[c]))
+		}
+
+		for i := range height {
+			var b strings.Builder
+			b.WriteString("│")
+			for c, w := range widths {
+				var text string
+				if i < len(cells[c]) {
+					text = truncateText(cells[c][i], w)
+				}
+				if numeric[c] {
+					text = strings.Repeat(" ", max(w-textWidth(text), 0)) + text
+				}
+				if header && r == 0 {
+					text = toBoldSansSerifText(text)
+				}
+				b.WriteString(" " + padRight(text, w) + " │")
+			}
+			lines = append(lines, b.String())
+		}
+
+		if header && r == 0 && len(rows) > 1 {
+			lines = append(lines, rule("├", "┼", "┤"))
+		}
+	}
+	return append(lines, rule("└", "┴", "┘"))
+}
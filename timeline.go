@@ -0,0 +1,129 @@
+package unidoc
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A timelinePeriod is a period of a mermaid timeline with its events.
+type timelinePeriod struct {
+	label   string
+	section string
+	events  []string
+}
+
+// A timeline holds the periods of a mermaid timeline in the order they are
+// listed.
+type timeline struct {
+	title   string
+	periods []timelinePeriod
+}
+
+// parseTimeline parses a timeline in the mermaid syntax, with a period and
+// its events on every line as "period : event : event". Lines starting with
+// a colon add events to the period before, and sections group the periods
+// after them.
+func parseTimeline(content string) (timeline, error) {
+	var t timeline
+	var section string
+	for i, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "%%") || line == "timeline" {
+			continue
+		}
+		if title, ok := strings.CutPrefix(line, "title "); ok {
+			t.title = strings.TrimSpace(title)
+			continue
+		}
+		if name, ok := strings.CutPrefix(line, "section "); ok {
+			section = strings.TrimSpace(name)
+			continue
+		}
+
+		fields := strings.Split(line, ":")
+		if len(fields) < 2 {
+			return t, fmt.Errorf("line %d: invalid period: %s", i+1, line)
+		}
+		var events []string
+		for _, event := range fields[1:] {
+			event = strings.Join(strings.Fields(seqBreaks.Replace(event)), " ")
+			if event != "" {
+				events = append(events, event)
+			}
+		}
+
+		label := strings.TrimSpace(fields[0])
+		if label == "" {
+			if len(t.periods) == 0 {
+				return t, fmt.Errorf("line %d: events without a period: %s", i+1, line)
+			}
+			last := &t.periods[len(t.periods)-1]
+			last.events = append(last.events, events...)
+			continue
+		}
+		t.periods = append(t.periods, timelinePeriod{label: label, section: section, events: events})
+	}
+	if len(t.periods) == 0 {
+		return t, fmt.Errorf("no periods")
+	}
+	return t, nil
+}
+
+// draw draws the periods of a timeline down a line, with the labels in a
+// column on the left, grouped by section, and the events wrapped to the
+// right:
+//
+//	2002 ●─ LinkedIn
+//	     │
+//	2004 ●─ Facebook
+//	     ├─ Google
+func (t timeline) draw(width int) []string {
+	labelWidth, indent := 0, ""
+	for _, period := range t.periods {
+		if period.section != "" {
+			labelWidth, indent = max(labelWidth, textWidth(period.section)), "  "
+		}
+	}
+	for _, period := range t.periods {
+		labelWidth = max(labelWidth, textWidth(indent+period.label))
+	}
+	labelWidth = min(labelWidth, width/3)
+	room := max(width-labelWidth-4, 1)
+
+	var lines []string
+	if t.title != "" {
+		lines = append(lines, toBoldSansSerifText(truncateText(t.title, width)))
+	}
+	section := ""
+	for i, period := range t.periods {
+		if period.section != section {
+			section = period.section
+			lines = append(lines, padRight(toBoldSansSerifText(truncateText(section, labelWidth)), labelWidth)+" │")
+		} else if i > 0 {
+			lines = append(lines, strings.Repeat(" ", labelWidth)+" │")
+		}
+		label := padRight(truncateText(indent+period.label, labelWidth), labelWidth)
+		if len(period.events) == 0 {
+			lines = append(lines, label+" ●")
+		}
+		for j, event := range period.events {
+			marker := "├─ "
+			if j == 0 {
+				marker = "●─ "
+			}
+			// Long events wrap beside the line, which ends with the last
+			more := "│  "
+			if i == len(t.periods)-1 && j == len(period.events)-1 {
+				more = "   "
+			}
+			for k, text := range wrapLine(event, room) {
+				if k > 0 {
+					marker = more
+				}
+				lines = append(lines, strings.TrimRight(label+" "+marker+text, " "))
+				label = strings.Repeat(" ", labelWidth)
+			}
+		}
+	}
+	return lines
+}
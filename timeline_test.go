@@ -0,0 +1,56 @@
+package unidoc
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTimeline(t *testing.T) {
+	tests := []struct {
+		name, language, content string
+		width                   int
+		want                    string
+	}{
+		{"mermaid", "mermaid", "timeline\n    title Social Media\n    2002 : LinkedIn\n    2004 : Facebook\n         : Google\n    2005 : YouTube", 40, `
+𝗦𝗼𝗰𝗶𝗮𝗹 𝗠𝗲𝗱𝗶𝗮
+2002 ●─ LinkedIn
+     │
+2004 ●─ Facebook
+     ├─ Google
+     │
+2005 ●─ YouTube`},
+		{"sections and wrapped events", "timeline", "timeline\n  section Past\n    Industry 1.0 : Machinery, Water power, Steam <br>power\n  section Now\n    Industry 4.0 : Internet : Robotics, Internet of Things", 42, `
+𝗣𝗮𝘀𝘁           │
+  Industry 1.0 ●─ Machinery, Water power,
+               │  Steam power
+𝗡𝗼𝘄            │
+  Industry 4.0 ●─ Internet
+               ├─ Robotics, Internet of
+                  Things`},
+		{"period without events", "timeline", "2020 :\n2021 : Launch", 30, `
+2020 ●
+     │
+2021 ●─ Launch`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := processTest(t, FencedBlock{Language: tt.language, Content: tt.content}, tt.width)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkOutput(t, got, tt.want)
+		})
+	}
+}
+
+func TestParseTimelineInvalid(t *testing.T) {
+	for _, content := range []string{": orphan", "just words", "timeline\n  title Only"} {
+		if _, err := parseTimeline(content); err == nil {
+			t.Errorf("parseTimeline(%q) accepted", content)
+		}
+	}
+	_, err := processTest(t, FencedBlock{Language: "mermaid", Content: "timeline\n  title Only"}, 30)
+	if !errors.Is(err, ErrUnprocessed) {
+		t.Errorf("got error %v, want ErrUnprocessed", err)
+	}
+}
@@ -0,0 +1,286 @@
+package unidoc
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// section is a heading of a document together with its position in the
+// heading tree.
+type section struct {
+	heading *gast.Heading
+	title   string // Plain text of the heading
+	id      string // Heading ID used as link anchor
+	depth   int    // Nesting depth in the heading tree, starting at 0
+	number  []int  // Hierarchical section number, such as 1.2.3
+}
+
+// outline returns the sections of the top-level headings of a document.
+// Depths follow the heading tree rather than raw levels, so that a jump from
+// a level 1 to a level 3 heading still nests only one step deeper.
+func outline(doc gast.Node, source []byte) []section {
+	var (
+		sections []section
+		levels   []int // Levels of the enclosing headings
+		counters []int // Section counters of the enclosing depths
+	)
+	for child := doc.FirstChild(); child != nil; child = child.NextSibling() {
+		heading, ok := child.(*gast.Heading)
+		if !ok {
+			continue
+		}
+
+		for len(levels) > 0 && levels[len(levels)-1] >= heading.Level {
+			levels = levels[:len(levels)-1]
+		}
+		depth := len(levels)
+		levels = append(levels, heading.Level)
+
+		// Deeper counters restart with every new section
+		if len(counters) > depth+1 {
+			counters = counters[:depth+1]
+		}
+		for len(counters) <= depth {
+			counters = append(counters, 0)
+		}
+		counters[depth]++
+
+		sections = append(sections, section{
+			heading: heading,
+			title:   strings.TrimSpace(plainText(heading, source)),
+			id:      headingID(heading),
+			depth:   depth,
+			number:  append([]int(nil), counters...),
+		})
+	}
+	return sections
+}
+
+// sectionNumber formats a hierarchical section number: 1.2.3.
+func sectionNumber(number []int) string {
+	parts := make([]string, len(number))
+	for i, n := range number {
+		parts[i] = strconv.Itoa(n)
+	}
+	return strings.Join(parts, ".")
+}
+
+// numberTokens matches the counter placeholders of a heading number format.
+var numberTokens = regexp.MustCompile(`\{[1aAiI]\}`)
+
+// formatSectionNumber formats a section number with a format such as
+// "{I}." or "{1}.{a}", where every placeholder takes the next counter:
+// {1} as a decimal, {a} and {A} as letters and {i} and {I} as Roman numerals.
+func formatSectionNumber(number []int, format string) string {
+	next := 0
+	return numberTokens.ReplaceAllStringFunc(format, func(token string) string {
+		if next >= len(number) {
+			return ""
+		}
+		n := number[next]
+		next++
+
+		switch token[1] {
+		case 'a':
+			return alphabeticNumber(n)
+		case 'A':
+			return strings.ToUpper(alphabeticNumber(n))
+		case 'i':
+			return strings.ToLower(romanNumber(n))
+		case 'I':
+			return romanNumber(n)
+		default:
+			return strconv.Itoa(n)
+		}
+	})
+}
+
+// alphabeticNumber returns a number in the sequence a, b, …, z, aa, ab, ….
+func alphabeticNumber(n int) string {
+	var s string
+	for ; n > 0; n = (n - 1) / 26 {
+		s = string(rune('a'+(n-1)%26)) + s
+	}
+	return s
+}
+
+// romanNumber returns a number as uppercase ASCII Roman numerals.
+func romanNumber(n int) string {
+	numerals := []struct {
+		value int
+		digit string
+	}{
+		{1000, "M"}, {900, "CM"}, {500, "D"}, {400, "CD"}, {100, "C"}, {90, "XC"},
+		{50, "L"}, {40, "XL"}, {10, "X"}, {9, "IX"}, {5, "V"}, {4, "IV"}, {1, "I"},
+	}
+
+	var b strings.Builder
+	for _, numeral := range numerals {
+		for ; n >= numeral.value; n -= numeral.value {
+			b.WriteString(numeral.digit)
+		}
+	}
+	return b.String()
+}
+
+// extendNumberFormat extends a heading number format to take counters
+// counters, repeating its last placeholder with the separator before it, or
+// a dot, for every counter it lacks: "{I}.{a}" takes three as "{I}.{a}.{a}".
+func extendNumberFormat(format string, counters int) string {
+	tokens := numberTokens.FindAllStringIndex(format, -1)
+	if len(tokens) == 0 || len(tokens) >= counters {
+		return format
+	}
+	last := tokens[len(tokens)-1]
+	separator := "."
+	if len(tokens) > 1 {
+		separator = format[tokens[len(tokens)-2][1]:last[0]]
+	}
+	extra := strings.Repeat(separator+format[last[0]:last[1]], counters-len(tokens))
+	return format[:last[1]] + extra + format[last[1]:]
+}
+
+// sectionLabel returns the section number of a section in the configured
+// format for its depth. Depths beyond the formats extend the last format.
+func (r *UnicodeRenderer) sectionLabel(s section) string {
+	formats := r.config.HeadingNumberFormats
+	switch {
+	case len(formats) == 0:
+		return sectionNumber(s.number)
+	case s.depth < len(formats):
+		if formats[s.depth] == "" {
+			return sectionNumber(s.number)
+		}
+		return formatSectionNumber(s.number, formats[s.depth])
+	case formats[len(formats)-1] == "":
+		return sectionNumber(s.number)
+	default:
+		return formatSectionNumber(s.number, extendNumberFormat(formats[len(formats)-1], len(s.number)))
+	}
+}
+
+// findSection returns the section of a heading.
+func (r *UnicodeRenderer) findSection(heading gast.Node) (section, bool) {
+	for _, s := range r.sections {
+		if gast.Node(s.heading) == heading {
+			return s, true
+		}
+	}
+	return section{}, false
+}
+
+// sectionReference returns the text replacing the destination of an internal
+// link to a heading ID, such as " (§2.1)", or "" if no heading has the ID.
+func (r *UnicodeRenderer) sectionReference(destination []byte) string {
+	id, ok := bytes.CutPrefix(destination, []byte("#"))
+	if !ok {
+		return ""
+	}
+	for _, s := range r.sections {
+		if s.id == string(id) {
+			if r.config.NumberHeadings {
+				return " (§" + strings.TrimRight(r.sectionLabel(s), ".:) ") + ")"
+			}
+			return " (→ " + r.toSmartDashes(s.title) + ")"
+		}
+	}
+	return ""
+}
+
+// A TableOfContents represents the place where the table of contents of the
+// document is rendered, written as a [TOC] paragraph.
+type TableOfContents struct {
+	gast.BaseBlock
+}
+
+// KindTableOfContents is a NodeKind of the TableOfContents node.
+var KindTableOfContents = gast.NewNodeKind("TableOfContents")
+
+// Kind implements Node.Kind.
+func (n *TableOfContents) Kind() gast.NodeKind {
+	return KindTableOfContents
+}
+
+// Dump implements Node.Dump.
+func (n *TableOfContents) Dump(source []byte, level int) {
+	gast.DumpHelper(n, source, level, nil, nil)
+}
+
+// tocTransformer replaces [TOC] paragraphs with TableOfContents nodes.
+type tocTransformer struct{}
+
+// newTOCTransformer returns a new ASTTransformer for [TOC] placeholders.
+func newTOCTransformer() parser.ASTTransformer {
+	return &tocTransformer{}
+}
+
+func (t *tocTransformer) Transform(doc *gast.Document, reader text.Reader, _ parser.Context) {
+	source := reader.Source()
+
+	var placeholders []gast.Node
+	for child := doc.FirstChild(); child != nil; child = child.NextSibling() {
+		if p, ok := child.(*gast.Paragraph); ok && p.Lines().Len() == 1 {
+			segment := p.Lines().At(0)
+			if bytes.EqualFold(util.TrimRightSpace(segment.Value(source)), []byte("[TOC]")) {
+				placeholders = append(placeholders, p)
+			}
+		}
+	}
+
+	for _, p := range placeholders {
+		doc.ReplaceChild(doc, p, &TableOfContents{})
+	}
+}
+
+// insertTableOfContents adds a table of contents at the start of a document,
+// after its front matter, unless the document places one itself.
+func insertTableOfContents(doc gast.Node) {
+	var after gast.Node
+	for child := doc.FirstChild(); child != nil; child = child.NextSibling() {
+		switch child.Kind() {
+		case KindTableOfContents:
+			return
+		case KindFrontMatter:
+			after = child
+		}
+	}
+
+	if after == nil {
+		doc.InsertBefore(doc, doc.FirstChild(), &TableOfContents{})
+	} else {
+		doc.InsertAfter(doc, after, &TableOfContents{})
+	}
+}
+
+// TableOfContents renderer
+func (r *UnicodeRenderer) renderTableOfContents(
+	w util.BufWriter,
+	_ []byte,
+	_ gast.Node,
+	entering bool,
+) (gast.WalkStatus, error) {
+	if !entering || len(r.sections) == 0 {
+		return gast.WalkSkipChildren, nil
+	}
+
+	var b strings.Builder
+	b.WriteString(toBoldSansSerifText("Contents") + "\n")
+	// Entries end with the section number, like the page numbers of a
+	// printed table of contents
+	for _, entry := range r.outlineEntries(r.config.TOCDepth, r.sectionLabel) {
+		b.WriteString(entry + "\n")
+	}
+	b.WriteString("\n")
+
+	if _, err := w.WriteString(b.String()); err != nil {
+		return gast.WalkStop, err
+	}
+	return gast.WalkSkipChildren, nil
+}
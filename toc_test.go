@@ -0,0 +1,188 @@
+package unidoc
+
+import "testing"
+
+const tocDocument = `# Guide
+
+[TOC]
+
+## Install
+
+See [usage](#usage).
+
+### Linux
+
+## Usage
+
+Back to [install](#install) or [nowhere](#missing).
+`
+
+func TestTableOfContents(t *testing.T) {
+	got := convertTest(t, tocDocument)
+	checkOutput(t, got, `
+█ 𝗚𝘂𝗶𝗱𝗲
+═══════
+
+𝗖𝗼𝗻𝘁𝗲𝗻𝘁𝘀
+█ Guide ···················· 1
+  ▓▓ Install ············· 1.1
+    ▒▒▒ Linux ·········· 1.1.1
+  ▓▓ Usage ··············· 1.2
+
+▓▓ 𝗜𝗻𝘀𝘁𝗮𝗹𝗹
+──────────
+
+See usage (→ Usage).
+
+▒▒▒ 𝗟𝗶𝗻𝘂𝘅
+
+▓▓ 𝗨𝘀𝗮𝗴𝗲
+────────
+
+Back to install (→ Install) or [nowhere] 🔗 <#missing>.`)
+}
+
+func TestTableOfContentsDepth(t *testing.T) {
+	got := convertTest(t, "# Guide\n\n[TOC]\n\n## Install\n\n### Linux", func(c *Config) { c.TOCDepth = 2 })
+	checkOutput(t, got, `
+█ 𝗚𝘂𝗶𝗱𝗲
+═══════
+
+𝗖𝗼𝗻𝘁𝗲𝗻𝘁𝘀
+█ Guide ···················· 1
+  ▓▓ Install ············· 1.1
+
+▓▓ 𝗜𝗻𝘀𝘁𝗮𝗹𝗹
+──────────
+
+▒▒▒ 𝗟𝗶𝗻𝘂𝘅`)
+}
+
+func TestTableOfContentsOption(t *testing.T) {
+	// The option adds a table at the start of a document without [TOC]
+	got := convertTest(t, "# A\n\n## B\n\ntext", func(c *Config) { c.TOC = true })
+	checkOutput(t, got, `
+𝗖𝗼𝗻𝘁𝗲𝗻𝘁𝘀
+█ A ························ 1
+  ▓▓ B ··················· 1.1
+
+█ 𝗔
+═══
+
+▓▓ 𝗕
+────
+
+text`)
+}
+
+func TestTableOfContentsEmpty(t *testing.T) {
+	checkOutput(t, convertTest(t, "No headings\n\n[TOC]"), `
+No headings`)
+}
+
+func TestNumberHeadings(t *testing.T) {
+	got := convertTest(t, tocDocument, func(c *Config) { c.NumberHeadings = true })
+	checkOutput(t, got, `
+█ 𝟭 𝗚𝘂𝗶𝗱𝗲
+═════════
+
+𝗖𝗼𝗻𝘁𝗲𝗻𝘁𝘀
+█ Guide ···················· 1
+  ▓▓ Install ············· 1.1
+    ▒▒▒ Linux ·········· 1.1.1
+  ▓▓ Usage ··············· 1.2
+
+▓▓ 𝟭.𝟭 𝗜𝗻𝘀𝘁𝗮𝗹𝗹
+──────────────
+
+See usage (§1.2).
+
+▒▒▒ 𝟭.𝟭.𝟭 𝗟𝗶𝗻𝘂𝘅
+
+▓▓ 𝟭.𝟮 𝗨𝘀𝗮𝗴𝗲
+────────────
+
+Back to install (§1.1) or [nowhere] 🔗 <#missing>.`)
+}
+
+func TestHeadingNumberFormats(t *testing.T) {
+	got := convertTest(t, "# Guide\n\n## Install\n\n### Linux\n\n## Usage\n\nSee [install](#install).", func(c *Config) {
+		c.NumberHeadings = true
+		c.HeadingNumberFormats = []string{"Part {I}:", "{I}.{a}"}
+	})
+	checkOutput(t, got, `
+█ 𝗣𝗮𝗿𝘁 𝗜: 𝗚𝘂𝗶𝗱𝗲
+═══════════════
+
+▓▓ 𝗜.𝗮 𝗜𝗻𝘀𝘁𝗮𝗹𝗹
+──────────────
+
+▒▒▒ 𝗜.𝗮.𝗮 𝗟𝗶𝗻𝘂𝘅
+
+▓▓ 𝗜.𝗯 𝗨𝘀𝗮𝗴𝗲
+────────────
+
+See install (§I.a).`)
+}
+
+func TestFormatSectionNumber(t *testing.T) {
+	tests := []struct {
+		format, want string
+	}{
+		{"{1}.{a}", "2.c"},
+		{"{A}-{i}", "B-iii"},
+		{"§{1}", "§2"},
+		{"{1}.{1}.{1}", "2.3.4"},
+		{"x", "x"},
+	}
+	for _, tt := range tests {
+		if got := formatSectionNumber([]int{2, 3, 4}, tt.format); got != tt.want {
+			t.Errorf("formatSectionNumber(%q) = %q, want %q", tt.format, got, tt.want)
+		}
+	}
+	if got := sectionNumber([]int{2, 3}); got != "2.3" {
+		t.Errorf("sectionNumber = %q, want 2.3", got)
+	}
+}
+
+func TestExtendNumberFormat(t *testing.T) {
+	tests := []struct {
+		format   string
+		counters int
+		want     string
+	}{
+		{"{I}.{a}", 3, "{I}.{a}.{a}"},
+		{"{1}-{A})", 4, "{1}-{A}-{A}-{A})"},
+		{"Part {I}:", 2, "Part {I}.{I}:"},
+		{"{1}.{1}", 2, "{1}.{1}"},
+		{"x", 3, "x"},
+	}
+	for _, tt := range tests {
+		if got := extendNumberFormat(tt.format, tt.counters); got != tt.want {
+			t.Errorf("extendNumberFormat(%q, %d) = %q, want %q", tt.format, tt.counters, got, tt.want)
+		}
+	}
+}
+
+func TestRomanNumber(t *testing.T) {
+	tests := []struct {
+		n            int
+		roman, alpha string
+	}{
+		{1, "I", "a"},
+		{4, "IV", "d"},
+		{9, "IX", "i"},
+		{14, "XIV", "n"},
+		{40, "XL", "an"},
+		{1994, "MCMXCIV", "bxr"},
+		{3999, "MMMCMXCIX", "ewu"},
+	}
+	for _, tt := range tests {
+		if got := romanNumber(tt.n); got != tt.roman {
+			t.Errorf("romanNumber(%d) = %q, want %q", tt.n, got, tt.roman)
+		}
+		if got := alphabeticNumber(tt.n); got != tt.alpha {
+			t.Errorf("alphabeticNumber(%d) = %q, want %q", tt.n, got, tt.alpha)
+		}
+	}
+}
@@ -13,3 +13,34 @@ func translateMap(s string, m map[rune]rune) string {
 		return inp
 	}, s)
 }
+
+// translateMapStrict translates a string using a rune mapping, reporting
+// whether every character of the string had a mapping.
+func translateMapStrict(s string, m map[rune]rune) (string, bool) {
+	complete := true
+	out := strings.Map(func(inp rune) rune {
+		out, exists := m[inp]
+		if !exists {
+			complete = false
+			return inp
+		}
+		return out
+	}, s)
+	return out, complete
+}
+
+// combineText places a combining mark after every character of a string. A
+// zero mark leaves the string unchanged.
+func combineText(s string, mark rune) string {
+	if mark == 0 {
+		return s
+	}
+	var b strings.Builder
+	for _, r := range s {
+		b.WriteRune(r)
+		if r != '\n' {
+			b.WriteRune(mark)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,26 @@
+package unidoc
+
+// toDoubleStruckText converts regular text to mathematical double-struck Unicode (ℝℕℤ)
+func toDoubleStruckText(text string) string {
+	// Mathematical Double-Struck Unicode mapping
+	m := map[rune]rune{
+		'A': '\U0001D538', 'B': '\U0001D539', 'C': '\U00002102', 'D': '\U0001D53B', 'E': '\U0001D53C',
+		'F': '\U0001D53D', 'G': '\U0001D53E', 'H': '\U0000210D', 'I': '\U0001D540', 'J': '\U0001D541',
+		'K': '\U0001D542', 'L': '\U0001D543', 'M': '\U0001D544', 'N': '\U00002115', 'O': '\U0001D546',
+		'P': '\U00002119', 'Q': '\U0000211A', 'R': '\U0000211D', 'S': '\U0001D54A', 'T': '\U0001D54B',
+		'U': '\U0001D54C', 'V': '\U0001D54D', 'W': '\U0001D54E', 'X': '\U0001D54F', 'Y': '\U0001D550',
+		'Z': '\U00002124',
+
+		'a': '\U0001D552', 'b': '\U0001D553', 'c': '\U0001D554', 'd': '\U0001D555', 'e': '\U0001D556',
+		'f': '\U0001D557', 'g': '\U0001D558', 'h': '\U0001D559', 'i': '\U0001D55A', 'j': '\U0001D55B',
+		'k': '\U0001D55C', 'l': '\U0001D55D', 'm': '\U0001D55E', 'n': '\U0001D55F', 'o': '\U0001D560',
+		'p': '\U0001D561', 'q': '\U0001D562', 'r': '\U0001D563', 's': '\U0001D564', 't': '\U0001D565',
+		'u': '\U0001D566', 'v': '\U0001D567', 'w': '\U0001D568', 'x': '\U0001D569', 'y': '\U0001D56A',
+		'z': '\U0001D56B',
+
+		'0': '\U0001D7D8', '1': '\U0001D7D9', '2': '\U0001D7DA', '3': '\U0001D7DB', '4': '\U0001D7DC',
+		'5': '\U0001D7DD', '6': '\U0001D7DE', '7': '\U0001D7DF', '8': '\U0001D7E0', '9': '\U0001D7E1',
+	}
+
+	return translateMap(text, m)
+}
@@ -4,7 +4,7 @@ package unidoc
 func toItalicScriptText(text string) string {
 	// Mathematical Script Unicode mapping
 	m := map[rune]rune{
-		'A': '\U0001D49C', 'B': '\U0001D4B7', 'C': '\U0001D49E', 'D': '\U0001D49F', 'E': '\U00002130',
+		'A': '\U0001D49C', 'B': '\U0000212C', 'C': '\U0001D49E', 'D': '\U0001D49F', 'E': '\U00002130',
 		'F': '\U00002131', 'G': '\U0001D4A2', 'H': '\U0000210B', 'I': '\U00002110', 'J': '\U0001D4A5',
 		'K': '\U0001D4A6', 'L': '\U00002112', 'M': '\U00002133', 'N': '\U0001D4A9', 'O': '\U0001D4AA',
 		'P': '\U0001D4AB', 'Q': '\U0001D4AC', 'R': '\U0000211B', 'S': '\U0001D4AE', 'T': '\U0001D4AF',
@@ -0,0 +1,16 @@
+package unidoc
+
+// toSmallCapsText converts lowercase letters to small capitals (ꜱᴍᴀʟʟ ᴄᴀᴘꜱ).
+// There is no small capital x, so it is left as it is.
+func toSmallCapsText(text string) string {
+	// Small capital Unicode mapping from the IPA and phonetic extensions
+	m := map[rune]rune{
+		'a': '\U00001D00', 'b': '\U00000299', 'c': '\U00001D04', 'd': '\U00001D05', 'e': '\U00001D07',
+		'f': '\U0000A730', 'g': '\U00000262', 'h': '\U0000029C', 'i': '\U0000026A', 'j': '\U00001D0A',
+		'k': '\U00001D0B', 'l': '\U0000029F', 'm': '\U00001D0D', 'n': '\U00000274', 'o': '\U00001D0F',
+		'p': '\U00001D18', 'q': '\U0000A7AF', 'r': '\U00000280', 's': '\U0000A731', 't': '\U00001D1B',
+		'u': '\U00001D1C', 'v': '\U00001D20', 'w': '\U00001D21', 'y': '\U0000028F', 'z': '\U00001D22',
+	}
+
+	return translateMap(text, m)
+}
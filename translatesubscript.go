@@ -0,0 +1,24 @@
+package unidoc
+
+// toSubscriptText converts text to Unicode subscript characters (ₙ₋₁). The
+// second result reports whether every character had a subscript form.
+func toSubscriptText(text string) (string, bool) {
+	// Unicode subscript mapping
+	m := map[rune]rune{
+		'0': '\U00002080', '1': '\U00002081', '2': '\U00002082', '3': '\U00002083', '4': '\U00002084',
+		'5': '\U00002085', '6': '\U00002086', '7': '\U00002087', '8': '\U00002088', '9': '\U00002089',
+
+		' ': ' ',
+		'+': '\U0000208A', '-': '\U0000208B', '−': '\U0000208B', '=': '\U0000208C', '(': '\U0000208D',
+		')': '\U0000208E',
+
+		'a': '\U00002090', 'e': '\U00002091', 'h': '\U00002095', 'i': '\U00001D62', 'j': '\U00002C7C',
+		'k': '\U00002096', 'l': '\U00002097', 'm': '\U00002098', 'n': '\U00002099', 'o': '\U00002092',
+		'p': '\U0000209A', 'r': '\U00001D63', 's': '\U0000209B', 't': '\U0000209C', 'u': '\U00001D64',
+		'v': '\U00001D65', 'x': '\U00002093',
+
+		'β': '\U00001D66', 'γ': '\U00001D67', 'ρ': '\U00001D68', 'φ': '\U00001D69', 'χ': '\U00001D6A',
+	}
+
+	return translateMapStrict(text, m)
+}
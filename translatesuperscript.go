@@ -0,0 +1,31 @@
+package unidoc
+
+// toSuperscriptText converts text to Unicode superscript characters (ⁿ⁺¹). The
+// second result reports whether every character had a superscript form.
+func toSuperscriptText(text string) (string, bool) {
+	// Unicode superscript mapping
+	m := map[rune]rune{
+		'0': '\U00002070', '1': '\U000000B9', '2': '\U000000B2', '3': '\U000000B3', '4': '\U00002074',
+		'5': '\U00002075', '6': '\U00002076', '7': '\U00002077', '8': '\U00002078', '9': '\U00002079',
+
+		' ': ' ',
+		'+': '\U0000207A', '-': '\U0000207B', '−': '\U0000207B', '=': '\U0000207C', '(': '\U0000207D',
+		')': '\U0000207E',
+
+		'a': '\U00001D43', 'b': '\U00001D47', 'c': '\U00001D9C', 'd': '\U00001D48', 'e': '\U00001D49',
+		'f': '\U00001DA0', 'g': '\U00001D4D', 'h': '\U000002B0', 'i': '\U00002071', 'j': '\U000002B2',
+		'k': '\U00001D4F', 'l': '\U000002E1', 'm': '\U00001D50', 'n': '\U0000207F', 'o': '\U00001D52',
+		'p': '\U00001D56', 'r': '\U000002B3', 's': '\U000002E2', 't': '\U00001D57', 'u': '\U00001D58',
+		'v': '\U00001D5B', 'w': '\U000002B7', 'x': '\U000002E3', 'y': '\U000002B8', 'z': '\U00001DBB',
+
+		'A': '\U00001D2C', 'B': '\U00001D2E', 'D': '\U00001D30', 'E': '\U00001D31', 'G': '\U00001D33',
+		'H': '\U00001D34', 'I': '\U00001D35', 'J': '\U00001D36', 'K': '\U00001D37', 'L': '\U00001D38',
+		'M': '\U00001D39', 'N': '\U00001D3A', 'O': '\U00001D3C', 'P': '\U00001D3E', 'R': '\U00001D3F',
+		'T': '\U00001D40', 'U': '\U00001D41', 'V': '\U00002C7D', 'W': '\U00001D42',
+
+		'α': '\U00001D45', 'β': '\U00001D5D', 'γ': '\U00001D5E', 'δ': '\U00001D5F', 'ε': '\U00001D4B',
+		'θ': '\U00001DBF', 'ι': '\U00001DA5', 'φ': '\U00001D60', 'χ': '\U00001D61',
+	}
+
+	return translateMapStrict(text, m)
+}
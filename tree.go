@@ -0,0 +1,238 @@
+package unidoc
+
+import (
+	"fmt"
+	"strings"
+
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/util"
+)
+
+// A treeNode is an entry of a directory tree.
+type treeNode struct {
+	name     string
+	dir      bool // Entry is a directory: it has children or ends with /
+	children []*treeNode
+}
+
+// child returns the child entry with a name, adding it if there is none.
+func (n *treeNode) child(name string) *treeNode {
+	for _, child := range n.children {
+		if strings.TrimSuffix(child.name, "/") == strings.TrimSuffix(name, "/") {
+			return child
+		}
+	}
+	child := &treeNode{name: name}
+	n.children = append(n.children, child)
+	return child
+}
+
+// parseTree parses a tree from lines indented by depth. Entries may also be
+// paths such as cmd/unidoc/main.go, whose directories are merged with the
+// entries before them. Tree connectors in front of entries count as
+// indentation, so that the output of the tree command can be pasted as is.
+func parseTree(content string) *treeNode {
+	root := &treeNode{}
+	type level struct {
+		indent int
+		node   *treeNode
+	}
+	var stack []level
+
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimLeft(line, " \t│├└─")
+		name := strings.TrimSpace(trimmed)
+		if name == "" {
+			continue
+		}
+		indent := textWidth(strings.ReplaceAll(line[:len(line)-len(trimmed)], "\t", "    "))
+
+		for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+			stack = stack[:len(stack)-1]
+		}
+		parent := root
+		if len(stack) > 0 {
+			parent = stack[len(stack)-1].node
+		}
+
+		// Split paths into their directories, keeping a trailing slash
+		parts := strings.Split(strings.TrimSuffix(name, "/"), "/")
+		if strings.HasSuffix(name, "/") {
+			parts[len(parts)-1] += "/"
+		}
+		node := parent
+		for i, part := range parts {
+			node = node.child(part)
+			if i < len(parts)-1 || strings.HasSuffix(part, "/") {
+				node.dir = true
+			}
+		}
+		stack = append(stack, level{indent, node})
+	}
+	return root
+}
+
+// treeEntry returns the name of an entry annotated in a style: "slash"
+// appends a slash to directories and "icons" puts an icon in front of
+// directories and files.
+func treeEntry(node *treeNode, annotate string) string {
+	name := node.name
+	dir := node.dir || len(node.children) > 0
+	switch annotate {
+	case "slash":
+		if dir && !strings.HasSuffix(name, "/") {
+			name += "/"
+		}
+	case "icons":
+		name = strings.TrimSuffix(name, "/")
+		if dir {
+			return "📁 " + name
+		}
+		return "📄 " + name
+	}
+	return name
+}
+
+// drawTree lays out a tree with connectors, no wider than width. A single
+// top-level entry is the root of the tree; several are connected like its
+// children.
+func drawTree(root *treeNode, annotate string, width int) []string {
+	var lines []string
+	var walk func(node *treeNode, prefix string)
+	walk = func(node *treeNode, prefix string) {
+		for i, child := range node.children {
+			connector, indent := "├── ", "│   "
+			if i == len(node.children)-1 {
+				connector, indent = "└── ", "    "
+			}
+			lines = append(lines, truncateText(prefix+connector+treeEntry(child, annotate), width))
+			walk(child, prefix+indent)
+		}
+	}
+
+	if len(root.children) == 1 {
+		top := root.children[0]
+		lines = append(lines, truncateText(treeEntry(top, annotate), width))
+		walk(top, "")
+	} else {
+		walk(root, "")
+	}
+	return lines
+}
+
+// checkTreeAnnotation checks the annotate attribute of a tree.
+func checkTreeAnnotation(annotate string) error {
+	switch annotate {
+	case "", "none", "slash", "icons":
+		return nil
+	default:
+		return fmt.Errorf("invalid tree annotation: %s", annotate)
+	}
+}
+
+// treeProcessor renders ```tree blocks of indented entries as directory
+// trees. The annotate attribute marks directories and files with "slash" or
+// "icons":
+//
+//	```tree {annotate=icons}
+//	cmd/
+//	  unidoc/
+//	    main.go
+//	go.mod
+type treeProcessor struct{}
+
+// ProcessBlock implements BlockProcessor.ProcessBlock.
+func (p treeProcessor) ProcessBlock(block FencedBlock, width int) ([]string, error) {
+	annotate := block.Attributes["annotate"]
+	if err := checkTreeAnnotation(annotate); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnprocessed, err)
+	}
+	lines := drawTree(parseTree(block.Content), annotate, width)
+	if len(lines) == 0 {
+		return nil, ErrUnprocessed
+	}
+	return lines, nil
+}
+
+// treeListAnnotation returns the annotate attribute of a list.
+func treeListAnnotation(node gast.Node) string {
+	value, _ := node.AttributeString("annotate")
+	annotate, _ := optionText(value)
+	return annotate
+}
+
+// hasClass reports whether one of the classes of a node is class.
+func hasClass(node gast.Node, class string) bool {
+	value, ok := node.AttributeString("class")
+	if !ok {
+		return false
+	}
+	classes, _ := value.([]byte)
+	for _, name := range strings.Fields(string(classes)) {
+		if name == class {
+			return true
+		}
+	}
+	return false
+}
+
+// listTree builds a tree from the items of a list, rendering the text of
+// every item. Items with a nested list are directories.
+func (r *UnicodeRenderer) listTree(source []byte, list gast.Node, parent *treeNode) error {
+	for item := list.FirstChild(); item != nil; item = item.NextSibling() {
+		var (
+			texts []string
+			lists []gast.Node
+		)
+		for child := item.FirstChild(); child != nil; child = child.NextSibling() {
+			if child.Kind() == gast.KindList {
+				lists = append(lists, child)
+				continue
+			}
+			text, err := r.renderChildren(source, child, r.width)
+			if err != nil {
+				return err
+			}
+			texts = append(texts, strings.Join(strings.Fields(text), " "))
+		}
+
+		name := strings.Join(texts, " ")
+		node := &treeNode{name: name, dir: strings.HasSuffix(name, "/") || len(lists) > 0}
+		parent.children = append(parent.children, node)
+		for _, nested := range lists {
+			if err := r.listTree(source, nested, node); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// renderTreeList renders a list with the tree class as a directory tree. The
+// annotate attribute must be valid; lists with others stay lists.
+func (r *UnicodeRenderer) renderTreeList(
+	w util.BufWriter,
+	source []byte,
+	node gast.Node,
+	entering bool,
+) (gast.WalkStatus, error) {
+	if !entering {
+		return gast.WalkContinue, nil
+	}
+
+	annotate := treeListAnnotation(node)
+	root := &treeNode{}
+	if err := r.listTree(source, node, root); err != nil {
+		return gast.WalkStop, err
+	}
+
+	var b strings.Builder
+	for _, line := range drawTree(root, annotate, r.width) {
+		b.WriteString(line + "\n")
+	}
+	b.WriteString("\n")
+	if _, err := w.WriteString(b.String()); err != nil {
+		return gast.WalkStop, err
+	}
+	return gast.WalkSkipChildren, nil
+}
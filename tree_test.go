@@ -0,0 +1,70 @@
+package unidoc
+
+import "testing"
+
+func TestTreeProcessor(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		annotate string
+		width    int
+		want     string
+	}{
+		{"entries", "cmd/\n  unidoc/\n    main.go\ngo.mod\nREADME.md\n", "", 30, `
+├── cmd/
+│   └── unidoc/
+│       └── main.go
+├── go.mod
+└── README.md`},
+		{"icons", "cmd/\n  unidoc/\n    main.go\ngo.mod\n", "icons", 30, `
+├── 📁 cmd
+│   └── 📁 unidoc
+│       └── 📄 main.go
+└── 📄 go.mod`},
+		{"paths", "a/b/c.go\na/d.go\n", "slash", 30, `
+a/
+├── b/
+│   └── c.go
+└── d.go`},
+		{"tabs", "src\n\tmain.go\n  odd\n", "", 30, `
+src
+├── main.go
+└── odd`},
+		{"truncated", "a-very-long-directory-name/\n  another-very-long-file-name.txt\n", "", 20, `
+a-very-long-directo…
+└── another-very-lo…`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			block := FencedBlock{Language: "tree", Content: tt.content, Attributes: map[string]string{"annotate": tt.annotate}}
+			got, err := processTest(t, block, tt.width)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkOutput(t, got, tt.want)
+		})
+	}
+}
+
+func TestTreeList(t *testing.T) {
+	tests := []struct {
+		name, input, want string
+	}{
+		{"plain", "{.tree}\n- cmd\n  - unidoc\n    - main.go\n- go.mod", `
+├── cmd
+│   └── unidoc
+│       └── main.go
+└── go.mod`},
+		{"icons", "{.tree annotate=icons}\n- *docs*\n  - [guide](x.md)\n- `go.mod`", `
+├── 📁 𝘥𝘰𝘤𝘴
+│   └── 📄 [guide] 🔗 <x.md>
+└── 📄 ⌜go.mod⌝`},
+		{"unknown annotation", "{.tree annotate=bogus}\n- a", `
+• a`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkOutput(t, convertTest(t, tt.input), tt.want)
+		})
+	}
+}